@@ -23,6 +23,7 @@ import (
 	ctrlruntimectrl "sigs.k8s.io/controller-runtime/pkg/controller"
 	ctrlruntimereconcile "sigs.k8s.io/controller-runtime/pkg/reconcile"
 	ctrlruntimesrc "sigs.k8s.io/controller-runtime/pkg/source"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/kuadrant/policy-machinery/machinery"
 )
@@ -30,17 +31,26 @@ import (
 const resourceStoreId = "resources"
 
 type ControllerOptions struct {
-	name               string
-	logger             logr.Logger
-	tracer             trace.Tracer
-	client             *dynamic.DynamicClient
-	manager            ctrlruntime.Manager
-	runnables          map[string]RunnableBuilder
-	reconcile          ReconcileFunc
-	policyKinds        []schema.GroupKind
-	objectKinds        []schema.GroupKind
-	objectLinks        []LinkFunc
-	allowTopologyLoops bool
+	name                   string
+	logger                 logr.Logger
+	tracer                 trace.Tracer
+	client                 *dynamic.DynamicClient
+	manager                ctrlruntime.Manager
+	runnables              map[string]RunnableBuilder
+	reconcile              ReconcileFunc
+	policyKinds            []schema.GroupKind
+	objectKinds            []schema.GroupKind
+	objectLinks            []LinkFunc
+	allowTopologyLoops     bool
+	topologyParallelism    int
+	gatewayControllerNames []gwapiv1.GatewayController
+	routeKinds             []schema.GroupKind
+	topologyExpansions     []TopologyExpansion
+	topologyNamespaces     bool
+	topologyListenerSets   bool
+	topologyRefGrants      bool
+	topologyEndpointSlices bool
+	requireReferenceGrants bool
 }
 
 type ControllerOption func(*ControllerOptions)
@@ -120,6 +130,82 @@ func AllowLoops() ControllerOption {
 	}
 }
 
+// WithTopologyParallelism sets the maximum number of concurrent steps to build the topology at each reconciliation.
+// Object link functions must be safe for concurrent use.
+func WithTopologyParallelism(n int) ControllerOption {
+	return func(o *ControllerOptions) {
+		o.topologyParallelism = n
+	}
+}
+
+// WithGatewayControllerNames scopes the topology to the GatewayClasses whose `controllerName` is one of the given names.
+// Gateways of other GatewayClasses, and everything that is reachable only through them, are left out of the topology.
+func WithGatewayControllerNames(controllerNames ...gwapiv1.GatewayController) ControllerOption {
+	return func(o *ControllerOptions) {
+		o.gatewayControllerNames = append(o.gatewayControllerNames, controllerNames...)
+	}
+}
+
+// WithRouteKinds sets the kinds of Gateway API routes included in the topology, among HTTPRoute, GRPCRoute, TCPRoute,
+// TLSRoute and UDPRoute. Defaults to HTTPRoutes and GRPCRoutes.
+// Building the topology fails if any of the kinds is not a supported kind of route.
+func WithRouteKinds(routeKinds ...schema.GroupKind) ControllerOption {
+	return func(o *ControllerOptions) {
+		o.routeKinds = append(o.routeKinds, routeKinds...)
+	}
+}
+
+// WithTopologyExpansions sets the expansions of the Gateway API topology, e.g. ExpandGatewayListeners,
+// ExpandTCPRouteRules, in place of the default ones (listeners, HTTPRoute rules, GRPCRoute rules and service ports).
+// Calling it with no expansions builds the topology with none.
+// Building the topology fails if any of the expansions is not a supported one.
+func WithTopologyExpansions(expansions ...TopologyExpansion) ControllerOption {
+	return func(o *ControllerOptions) {
+		if o.topologyExpansions == nil {
+			o.topologyExpansions = []TopologyExpansion{}
+		}
+		o.topologyExpansions = append(o.topologyExpansions, expansions...)
+	}
+}
+
+// WithTopologyNamespaces includes the Namespaces watched in the topology, linked to the Gateways and routes that live in
+// them.
+func WithTopologyNamespaces() ControllerOption {
+	return func(o *ControllerOptions) {
+		o.topologyNamespaces = true
+	}
+}
+
+// WithTopologyListenerSets includes the ListenerSets watched in the topology, along with their listeners.
+func WithTopologyListenerSets() ControllerOption {
+	return func(o *ControllerOptions) {
+		o.topologyListenerSets = true
+	}
+}
+
+// WithTopologyReferenceGrants includes the ReferenceGrants watched in the topology, as objects.
+func WithTopologyReferenceGrants() ControllerOption {
+	return func(o *ControllerOptions) {
+		o.topologyRefGrants = true
+	}
+}
+
+// RequireReferenceGrants includes the ReferenceGrants watched in the topology, and restricts the links from routes to
+// Services in other namespaces to the ones permitted by a ReferenceGrant.
+func RequireReferenceGrants() ControllerOption {
+	return func(o *ControllerOptions) {
+		o.topologyRefGrants = true
+		o.requireReferenceGrants = true
+	}
+}
+
+// WithTopologyEndpointSlices includes the EndpointSlices watched in the topology, linked from their Services.
+func WithTopologyEndpointSlices() ControllerOption {
+	return func(o *ControllerOptions) {
+		o.topologyEndpointSlices = true
+	}
+}
+
 func NewController(f ...ControllerOption) *Controller {
 	opts := &ControllerOptions{
 		name:      "controller",
@@ -141,7 +227,7 @@ func NewController(f ...ControllerOption) *Controller {
 		client:    opts.client,
 		manager:   opts.manager,
 		cache:     &CacheStore{},
-		topology:  newGatewayAPITopologyBuilder(opts.policyKinds, opts.objectKinds, opts.objectLinks, opts.allowTopologyLoops),
+		topology:  newGatewayAPITopologyBuilder(opts),
 		runnables: map[string]Runnable{},
 		reconcile: opts.reconcile,
 	}
@@ -100,6 +100,11 @@ func TestControllerOptions(t *testing.T) {
 	if opts.allowTopologyLoops == false {
 		t.Errorf("expected allowTopologyLoops true, got false")
 	}
+
+	WithGatewayControllerNames("my-gateway-controller")(opts)
+	if len(opts.gatewayControllerNames) != 1 || opts.gatewayControllerNames[0] != "my-gateway-controller" {
+		t.Errorf("expected gateway controller names [my-gateway-controller], got %v", opts.gatewayControllerNames)
+	}
 }
 
 func TestNewController(t *testing.T) {
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"fmt"
+	"reflect"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/tools/cache"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwapiv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+	gwapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+var gatewayAPIScheme = runtime.NewScheme()
+
+func init() {
+	_ = gwapiv1.Install(gatewayAPIScheme)
+	_ = gwapiv1beta1.Install(gatewayAPIScheme)
+	_ = gwapiv1alpha2.Install(gatewayAPIScheme)
+	_ = gwapiv1alpha3.Install(gatewayAPIScheme)
+}
+
+// ConvertGatewayAPIVersion returns a function that rewrites the apiVersion of Gateway API objects served at any version
+// of the Gateway API group to the given version. Use it as a mutate function of TransformFunc to convert objects served
+// at older versions (v1alpha2, v1beta1) into the typed structs of a newer one, whose fields are the same.
+func ConvertGatewayAPIVersion(version string) func(*unstructured.Unstructured) {
+	return func(obj *unstructured.Unstructured) {
+		gvk := obj.GroupVersionKind()
+		if gvk.Group != gwapiv1.GroupName || gvk.Version == version {
+			return
+		}
+		gvk.Version = version
+		obj.SetGroupVersionKind(gvk)
+	}
+}
+
+// GatewayAPITransformFunc returns a cache.TransformFunc that converts unstructured Gateway API objects served at any
+// version into the typed object T, normalizing their apiVersion to the version of T.
+// E.g. GatewayAPITransformFunc[*gwapiv1.TCPRoute]() converts v1alpha2 TCPRoutes into v1 ones.
+func GatewayAPITransformFunc[T Object]() cache.TransformFunc {
+	version := gwapiv1.GroupVersion.Version
+	if t := reflect.TypeOf((*T)(nil)).Elem(); t.Kind() == reflect.Pointer {
+		if obj, ok := reflect.New(t.Elem()).Interface().(runtime.Object); ok {
+			if gvks, _, err := gatewayAPIScheme.ObjectKinds(obj); err == nil && len(gvks) > 0 {
+				version = gvks[0].Version
+			}
+		}
+	}
+	return TransformFunc[T](ConvertGatewayAPIVersion(version))
+}
+
+// WatchGatewayAPIResource returns a runnable builder that watches a Gateway API resource at any version served by the
+// cluster, e.g. TCPRoutesV1alpha2Resource, and normalizes the objects into the type of obj, e.g. *gwapiv1.TCPRoute.
+// The objects are then stored under the same group kind regardless of the version they are served at, so the topology
+// built by the controller includes them as if they were served at the version of obj.
+func WatchGatewayAPIResource[T Object](obj T, resource schema.GroupVersionResource, namespace string, options ...RunnableBuilderOption[T]) RunnableBuilder {
+	return Watch(obj, resource, namespace, append([]RunnableBuilderOption[T]{WithTransformerFunc[T](GatewayAPITransformFunc[T]())}, options...)...)
+}
+
+// ServedResource returns the first of the given resources that is served by the cluster, as per API discovery.
+// List the versions of a resource in order of preference, e.g.:
+//
+//	ServedResource(discoveryClient, TCPRoutesResource, TCPRoutesV1alpha2Resource)
+func ServedResource(client discovery.ServerResourcesInterface, resources ...schema.GroupVersionResource) (schema.GroupVersionResource, error) {
+	for _, resource := range resources {
+		list, err := client.ServerResourcesForGroupVersion(resource.GroupVersion().String())
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return schema.GroupVersionResource{}, err
+		}
+		for _, apiResource := range list.APIResources {
+			if apiResource.Name == resource.Resource {
+				return resource, nil
+			}
+		}
+	}
+	return schema.GroupVersionResource{}, fmt.Errorf("none of the resources %v is served", resources)
+}
@@ -0,0 +1,110 @@
+//go:build unit
+
+package controller
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestGatewayAPITransformFunc(t *testing.T) {
+	tcpRoute := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "gateway.networking.k8s.io/v1alpha2",
+		"kind":       "TCPRoute",
+		"metadata":   map[string]any{"name": "my-tcp-route", "namespace": "my-namespace"},
+		"spec": map[string]any{
+			"parentRefs": []any{map[string]any{"name": "my-gateway"}},
+			"rules":      []any{map[string]any{"backendRefs": []any{map[string]any{"name": "my-service", "port": int64(5432)}}}},
+		},
+	}}
+
+	obj, err := GatewayAPITransformFunc[*gwapiv1.TCPRoute]()(tcpRoute)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	route, ok := obj.(*gwapiv1.TCPRoute)
+	if !ok {
+		t.Fatalf("expected a *gwapiv1.TCPRoute, got %T", obj)
+	}
+	if gvk := route.GroupVersionKind(); gvk != gwapiv1.SchemeGroupVersion.WithKind("TCPRoute") {
+		t.Errorf("expected the object to be normalized to v1, got %s", gvk)
+	}
+	if len(route.Spec.Rules) != 1 || len(route.Spec.Rules[0].BackendRefs) != 1 || route.Spec.Rules[0].BackendRefs[0].Name != "my-service" {
+		t.Errorf("expected the backendRefs of the route to be preserved, got %v", route.Spec.Rules)
+	}
+
+	referenceGrant := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "gateway.networking.k8s.io/v1alpha2",
+		"kind":       "ReferenceGrant",
+		"metadata":   map[string]any{"name": "my-reference-grant", "namespace": "my-namespace"},
+	}}
+	obj, err = GatewayAPITransformFunc[*gwapiv1beta1.ReferenceGrant]()(referenceGrant)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if gvk := obj.(*gwapiv1beta1.ReferenceGrant).GroupVersionKind(); gvk != gwapiv1beta1.SchemeGroupVersion.WithKind("ReferenceGrant") {
+		t.Errorf("expected the object to be normalized to v1beta1, got %s", gvk)
+	}
+}
+
+func TestServedResource(t *testing.T) {
+	client := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	client.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: gwapiv1.SchemeGroupVersion.String(),
+			APIResources: []metav1.APIResource{{Name: "gateways"}, {Name: "httproutes"}},
+		},
+		{
+			GroupVersion: "gateway.networking.k8s.io/v1alpha2",
+			APIResources: []metav1.APIResource{{Name: "tcproutes"}, {Name: "grpcroutes"}},
+		},
+	}
+
+	testCases := []struct {
+		name        string
+		resources   []schema.GroupVersionResource
+		expected    schema.GroupVersionResource
+		expectedErr bool
+	}{
+		{
+			name:      "preferred version served",
+			resources: []schema.GroupVersionResource{HTTPRoutesResource, HTTPRoutesV1beta1Resource},
+			expected:  HTTPRoutesResource,
+		},
+		{
+			name:      "older version served",
+			resources: []schema.GroupVersionResource{TCPRoutesResource, TCPRoutesV1alpha2Resource},
+			expected:  TCPRoutesV1alpha2Resource,
+		},
+		{
+			name:        "not served",
+			resources:   []schema.GroupVersionResource{UDPRoutesResource, UDPRoutesV1alpha2Resource},
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resource, err := ServedResource(client, tc.resources...)
+			if tc.expectedErr {
+				if err == nil {
+					t.Errorf("expected an error, got resource %s", resource)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if resource != tc.expected {
+				t.Errorf("expected resource %s, got %s", tc.expected, resource)
+			}
+		})
+	}
+}
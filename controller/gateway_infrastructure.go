@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"strings"
+
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kuadrant/policy-machinery/machinery"
+)
+
+const gatewayAddressKeyPrefix = "address:"
+
+// addressFieldPaths are the field paths of the external addresses of the infrastructure objects that can match the
+// addresses reported in the status of a Gateway, e.g. the addresses of a Service of type LoadBalancer.
+// In-cluster addresses, such as the cluster IPs of Services, are left out, so unrelated objects are not matched.
+var addressFieldPaths = [][]string{
+	strings.Split("spec.externalIPs[*]", "."),
+	strings.Split("status.loadBalancer.ingress[*].ip", "."),
+	strings.Split("status.loadBalancer.ingress[*].hostname", "."),
+}
+
+// GatewayInfrastructureLinkOptions tells how to match the infrastructure objects generated by the gateway
+// implementations with the Gateways they were created for.
+type GatewayInfrastructureLinkOptions struct {
+	// MatchStatusAddresses links the infrastructure objects whose addresses match any of the addresses in the status of
+	// a Gateway, regardless of labels and namespaces
+	MatchStatusAddresses bool
+}
+
+type GatewayInfrastructureLinkOptionsFunc func(*GatewayInfrastructureLinkOptions)
+
+// MatchGatewayStatusAddresses links the infrastructure objects whose addresses match any of the addresses in the
+// `status.addresses` field of a Gateway, in addition to the ones labelled with the name of the Gateway.
+// Useful for gateway implementations that create the infrastructure in a namespace other than the one of the Gateway.
+// The addresses of an object are read from the fields `spec.externalIPs` and `status.loadBalancer.ingress`, as in core
+// Services. Cluster IPs are not matched.
+func MatchGatewayStatusAddresses() GatewayInfrastructureLinkOptionsFunc {
+	return func(o *GatewayInfrastructureLinkOptions) {
+		o.MatchStatusAddresses = true
+	}
+}
+
+// LinkGatewayToInfrastructureFunc returns a link function that teaches a topology how to link the infrastructure
+// objects of kind `to` that the gateway implementations generate for the Gateways (e.g. Deployments, Services,
+// HorizontalPodAutoscalers), based on the `gateway.networking.k8s.io/gateway-name` label of the objects.
+// The objects are linked from the Gateway with the name in the label, in the namespace of the object.
+func LinkGatewayToInfrastructureFunc(to schema.GroupKind, options ...GatewayInfrastructureLinkOptionsFunc) LinkFunc {
+	o := &GatewayInfrastructureLinkOptions{}
+	for _, f := range options {
+		f(o)
+	}
+
+	return func(objs Store) machinery.LinkFunc {
+		gateways := lo.Map(objs.FilterByGroupKind(machinery.GatewayGroupKind), func(obj Object, _ int) *machinery.Gateway {
+			return &machinery.Gateway{Gateway: ObjectAs[*gwapiv1.Gateway](obj, 0)}
+		})
+		gateways = lo.Filter(gateways, func(gateway *machinery.Gateway, _ int) bool { return gateway.Gateway != nil })
+
+		parentKeys := func(gateway *machinery.Gateway) []string {
+			keys := []string{namespacedName(gateway.Namespace, gateway.Name)}
+			if o.MatchStatusAddresses {
+				keys = append(keys, lo.Map(gateway.Status.Addresses, func(address gwapiv1.GatewayStatusAddress, _ int) string {
+					return gatewayAddressKeyPrefix + address.Value
+				})...)
+			}
+			return keys
+		}
+		unstructuredContent := unstructuredContentFunc(objs)
+		childKeys := func(child machinery.Object) []string {
+			content, err := unstructuredContent(child)
+			if err != nil {
+				return nil
+			}
+			var keys []string
+			labels, _, _ := unstructured.NestedStringMap(content, "metadata", "labels")
+			if gatewayName := labels[gwapiv1.GatewayNameLabelKey]; gatewayName != "" {
+				keys = append(keys, namespacedName(child.GetNamespace(), gatewayName))
+			}
+			if o.MatchStatusAddresses {
+				for _, path := range addressFieldPaths {
+					for _, address := range valuesAtFieldPath(content, path) {
+						if address, ok := address.(string); ok && address != "" {
+							keys = append(keys, gatewayAddressKeyPrefix+address)
+						}
+					}
+				}
+			}
+			return keys
+		}
+		return machinery.IndexedLinkFunc(machinery.GatewayGroupKind, to, gateways, parentKeys, childKeys)
+	}
+}
+
+// WatchGatewayInfrastructure returns a runnable builder that watches the objects of a resource that the gateway
+// implementations generate for the Gateways, i.e. the ones labelled with `gateway.networking.k8s.io/gateway-name`.
+// Use it along with LinkGatewayToInfrastructureFunc. Watch all objects of the resource instead when matching the
+// addresses in the status of the Gateways, since those objects may not be labelled.
+func WatchGatewayInfrastructure[T Object](obj T, resource schema.GroupVersionResource, namespace string, options ...RunnableBuilderOption[T]) RunnableBuilder {
+	return Watch(obj, resource, namespace, append([]RunnableBuilderOption[T]{FilterResourcesByLabel[T](gwapiv1.GatewayNameLabelKey)}, options...)...)
+}
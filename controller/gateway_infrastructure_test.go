@@ -0,0 +1,134 @@
+//go:build unit
+
+package controller
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kuadrant/policy-machinery/machinery"
+)
+
+func TestLinkGatewayToInfrastructureFunc(t *testing.T) {
+	deploymentKind := schema.GroupKind{Group: appsv1.GroupName, Kind: "Deployment"}
+
+	store := Store{
+		"gateway-1": machinery.BuildGateway(func(g *gwapiv1.Gateway) {
+			g.UID = types.UID("gateway-1")
+			g.Status.Addresses = []gwapiv1.GatewayStatusAddress{{Value: "10.0.0.1"}, {Value: "gw.example.com"}}
+		}),
+		"gateway-2": machinery.BuildGateway(func(g *gwapiv1.Gateway) {
+			g.Name = "other-gateway"
+			g.UID = types.UID("gateway-2")
+		}),
+	}
+
+	buildDeployment := func(namespace, gatewayName string) *appsv1.Deployment {
+		d := &appsv1.Deployment{
+			TypeMeta:   metav1.TypeMeta{APIVersion: appsv1.SchemeGroupVersion.String(), Kind: "Deployment"},
+			ObjectMeta: metav1.ObjectMeta{Name: "my-gateway-deployment", Namespace: namespace},
+		}
+		if gatewayName != "" {
+			d.Labels = map[string]string{gwapiv1.GatewayNameLabelKey: gatewayName}
+		}
+		return d
+	}
+	buildService := func(namespace string, f ...func(*corev1.Service)) *corev1.Service {
+		return machinery.BuildService(append([]func(*corev1.Service){func(s *corev1.Service) {
+			s.Namespace = namespace
+		}}, f...)...)
+	}
+	withIngress := func(ingress corev1.LoadBalancerIngress) func(*corev1.Service) {
+		return func(s *corev1.Service) {
+			s.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{ingress}
+		}
+	}
+
+	testCases := []struct {
+		name            string
+		to              schema.GroupKind
+		options         []GatewayInfrastructureLinkOptionsFunc
+		child           Object
+		expectedParents []string
+	}{
+		{
+			name:            "labelled object",
+			to:              deploymentKind,
+			child:           buildDeployment("my-namespace", "my-gateway"),
+			expectedParents: []string{"gateway.gateway.networking.k8s.io:my-namespace/my-gateway"},
+		},
+		{
+			name:  "labelled object in another namespace",
+			to:    deploymentKind,
+			child: buildDeployment("other-namespace", "my-gateway"),
+		},
+		{
+			name:  "object not labelled",
+			to:    deploymentKind,
+			child: buildDeployment("my-namespace", ""),
+		},
+		{
+			name:  "status addresses not matched by default",
+			to:    machinery.ServiceGroupKind,
+			child: buildService("gateway-system", withIngress(corev1.LoadBalancerIngress{IP: "10.0.0.1"})),
+		},
+		{
+			name:            "matching status address",
+			to:              machinery.ServiceGroupKind,
+			options:         []GatewayInfrastructureLinkOptionsFunc{MatchGatewayStatusAddresses()},
+			child:           buildService("gateway-system", withIngress(corev1.LoadBalancerIngress{IP: "10.0.0.1"})),
+			expectedParents: []string{"gateway.gateway.networking.k8s.io:my-namespace/my-gateway"},
+		},
+		{
+			name:            "matching status hostname",
+			to:              machinery.ServiceGroupKind,
+			options:         []GatewayInfrastructureLinkOptionsFunc{MatchGatewayStatusAddresses()},
+			child:           buildService("gateway-system", withIngress(corev1.LoadBalancerIngress{Hostname: "gw.example.com"})),
+			expectedParents: []string{"gateway.gateway.networking.k8s.io:my-namespace/my-gateway"},
+		},
+		{
+			name:            "matching external ip",
+			to:              machinery.ServiceGroupKind,
+			options:         []GatewayInfrastructureLinkOptionsFunc{MatchGatewayStatusAddresses()},
+			child:           buildService("gateway-system", func(s *corev1.Service) { s.Spec.ExternalIPs = []string{"10.0.0.1"} }),
+			expectedParents: []string{"gateway.gateway.networking.k8s.io:my-namespace/my-gateway"},
+		},
+		{
+			name:    "unrelated service with a colliding cluster ip",
+			to:      machinery.ServiceGroupKind,
+			options: []GatewayInfrastructureLinkOptionsFunc{MatchGatewayStatusAddresses()},
+			child: buildService("other-namespace", func(s *corev1.Service) {
+				s.Spec.ClusterIP = "10.0.0.1"
+				s.Spec.ClusterIPs = []string{"10.0.0.1"}
+			}),
+		},
+		{
+			name:    "status address not matching",
+			to:      machinery.ServiceGroupKind,
+			options: []GatewayInfrastructureLinkOptionsFunc{MatchGatewayStatusAddresses()},
+			child:   buildService("gateway-system", withIngress(corev1.LoadBalancerIngress{IP: "10.0.0.2"})),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			link := LinkGatewayToInfrastructureFunc(tc.to, tc.options...)(store)
+			if link.From != machinery.GatewayGroupKind || link.To != tc.to {
+				t.Errorf("expected link from %s to %s, got from %s to %s", machinery.GatewayGroupKind, tc.to, link.From, link.To)
+			}
+			parents := lo.Map(link.Func(asMachineryObject(tc.child)), machinery.MapObjectToLocatorFunc)
+			slices.Sort(parents)
+			if !slices.Equal(parents, tc.expectedParents) {
+				t.Errorf("expected parents %v, got %v", tc.expectedParents, parents)
+			}
+		})
+	}
+}
@@ -0,0 +1,267 @@
+package controller
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/kuadrant/policy-machinery/machinery"
+)
+
+const fieldPathListWildcard = "[*]"
+
+// FieldPathLinkOptions tells how to read the group, kind, name and namespace of a referent from each reference
+// found at the field path of a child object.
+// All fields are paths relative to the reference, with segments separated by dots.
+type FieldPathLinkOptions struct {
+	GroupField      string
+	APIVersionField string
+	KindField       string
+	NameField       string
+	NamespaceField  string
+
+	// DefaultGroup and DefaultKind are assumed when the reference does not specify a group or a kind respectively
+	DefaultGroup *string
+	DefaultKind  *string
+}
+
+type FieldPathLinkOptionsFunc func(*FieldPathLinkOptions)
+
+// WithGroupField sets the path to the group of the referent within the reference. Defaults to `group`.
+func WithGroupField(path string) FieldPathLinkOptionsFunc {
+	return func(o *FieldPathLinkOptions) {
+		o.GroupField = path
+	}
+}
+
+// WithAPIVersionField sets the path to the API version of the referent within the reference.
+// The group of the referent is then parsed from the API version, e.g. as in `metadata.ownerReferences`.
+func WithAPIVersionField(path string) FieldPathLinkOptionsFunc {
+	return func(o *FieldPathLinkOptions) {
+		o.APIVersionField = path
+	}
+}
+
+// WithKindField sets the path to the kind of the referent within the reference. Defaults to `kind`.
+func WithKindField(path string) FieldPathLinkOptionsFunc {
+	return func(o *FieldPathLinkOptions) {
+		o.KindField = path
+	}
+}
+
+// WithNameField sets the path to the name of the referent within the reference. Defaults to `name`.
+func WithNameField(path string) FieldPathLinkOptionsFunc {
+	return func(o *FieldPathLinkOptions) {
+		o.NameField = path
+	}
+}
+
+// WithNamespaceField sets the path to the namespace of the referent within the reference. Defaults to `namespace`.
+// References that do not specify a namespace default to the namespace of the child object.
+func WithNamespaceField(path string) FieldPathLinkOptionsFunc {
+	return func(o *FieldPathLinkOptions) {
+		o.NamespaceField = path
+	}
+}
+
+// WithDefaultGroupKind sets the group and kind assumed for references that omit them, e.g. Gateway API parentRefs
+// default to `gateway.networking.k8s.io/Gateway`.
+func WithDefaultGroupKind(gk schema.GroupKind) FieldPathLinkOptionsFunc {
+	return func(o *FieldPathLinkOptions) {
+		o.DefaultGroup = &gk.Group
+		o.DefaultKind = &gk.Kind
+	}
+}
+
+// LinkFromFieldPath returns a link function that teaches a topology how to link objects of kind `to` from objects of
+// kind `from` stored in the controller's cache, based on the references found at the given field path of the child.
+//
+// The field path is a sequence of fields separated by dots. A field suffixed with `[*]` expands to all items of the
+// list, e.g. `spec.targetRef`, `spec.parentRefs[*]`, `metadata.ownerReferences[*]`.
+// Each reference found at the field path is expected to be either an object from which the group, kind, name and
+// namespace of the referent are read according to the options, or a string holding the name of a referent of kind
+// `from` in the namespace of the child (e.g. `spec.gatewayClassName`).
+// References to a group/kind other than `from` are ignored.
+//
+// Parents are indexed by namespace and name once per store, so each reference of a child is resolved in constant time.
+func LinkFromFieldPath(from, to schema.GroupKind, fieldPath string, options ...FieldPathLinkOptionsFunc) LinkFunc {
+	o := &FieldPathLinkOptions{
+		GroupField:     "group",
+		KindField:      "kind",
+		NameField:      "name",
+		NamespaceField: "namespace",
+	}
+	for _, f := range options {
+		f(o)
+	}
+
+	segments := strings.Split(fieldPath, ".")
+
+	return func(objs Store) machinery.LinkFunc {
+		parents := lo.Map(objs.FilterByGroupKind(from), func(obj Object, _ int) machinery.Object {
+			return asMachineryObject(obj)
+		})
+		parentKeys := func(parent machinery.Object) []string {
+			return []string{namespacedName(parent.GetNamespace(), parent.GetName())}
+		}
+		unstructuredContent := unstructuredContentFunc(objs)
+		childKeys := func(child machinery.Object) []string {
+			content, err := unstructuredContent(child)
+			if err != nil {
+				return nil
+			}
+			return lo.FlatMap(valuesAtFieldPath(content, segments), func(ref any, _ int) []string {
+				namespace, name, ok := o.referent(ref, from, child.GetNamespace())
+				if !ok {
+					return nil
+				}
+				// cluster-scoped parents are indexed without namespace
+				return []string{namespacedName(namespace, name), namespacedName("", name)}
+			})
+		}
+		return machinery.IndexedLinkFunc(from, to, parents, parentKeys, childKeys)
+	}
+}
+
+// referent returns the namespace and name of the object a reference points to, if the reference points to an object
+// of the given group/kind
+func (o *FieldPathLinkOptions) referent(ref any, gk schema.GroupKind, defaultNamespace string) (string, string, bool) {
+	if name, ok := ref.(string); ok {
+		return defaultNamespace, name, name != ""
+	}
+	fields, ok := ref.(map[string]any)
+	if !ok {
+		return "", "", false
+	}
+
+	group, found := stringAtFieldPath(fields, o.GroupField)
+	if o.APIVersionField != "" {
+		if apiVersion, ok := stringAtFieldPath(fields, o.APIVersionField); ok {
+			gv, err := schema.ParseGroupVersion(apiVersion)
+			if err != nil {
+				return "", "", false
+			}
+			group, found = gv.Group, true
+		}
+	}
+	if !found && o.DefaultGroup != nil {
+		group = *o.DefaultGroup
+	}
+	kind, found := stringAtFieldPath(fields, o.KindField)
+	if !found && o.DefaultKind != nil {
+		kind = *o.DefaultKind
+	}
+	if group != gk.Group || kind != gk.Kind {
+		return "", "", false
+	}
+
+	name, _ := stringAtFieldPath(fields, o.NameField)
+	namespace, found := stringAtFieldPath(fields, o.NamespaceField)
+	if !found || namespace == "" {
+		namespace = defaultNamespace
+	}
+	return namespace, name, name != ""
+}
+
+// valuesAtFieldPath returns all values found at a field path of an unstructured object, expanding lists wherever a
+// segment of the path is suffixed with `[*]`
+func valuesAtFieldPath(obj any, segments []string) []any {
+	if len(segments) == 0 {
+		return []any{obj}
+	}
+	fields, ok := obj.(map[string]any)
+	if !ok {
+		return nil
+	}
+	segment := segments[0]
+	field, expand := strings.CutSuffix(segment, fieldPathListWildcard)
+	value, ok := fields[field]
+	if !ok || value == nil {
+		return nil
+	}
+	if !expand {
+		return valuesAtFieldPath(value, segments[1:])
+	}
+	items, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+	return lo.FlatMap(items, func(item any, _ int) []any {
+		return valuesAtFieldPath(item, segments[1:])
+	})
+}
+
+func stringAtFieldPath(obj map[string]any, path string) (string, bool) {
+	if path == "" {
+		return "", false
+	}
+	value, found, err := unstructured.NestedString(obj, strings.Split(path, ".")...)
+	return value, found && err == nil
+}
+
+// unstructuredMemos are the unstructured representations of the objects of the stores being built into topologies,
+// indexed by store and then by object locator
+var unstructuredMemos sync.Map
+
+// memoizeUnstructuredContent keeps the unstructured representation of each object of a store, once converted, for all
+// the link functions that read it, until the returned function is called, e.g. while the store is built into a topology
+func memoizeUnstructuredContent(objs Store) func() {
+	id := reflect.ValueOf(objs).Pointer()
+	unstructuredMemos.Store(id, &sync.Map{})
+	return func() {
+		unstructuredMemos.Delete(id)
+	}
+}
+
+// unstructuredContentFunc returns a function that returns the unstructured representation of the objects of a store,
+// memoized if the store is being built into a topology
+func unstructuredContentFunc(objs Store) func(machinery.Object) (map[string]any, error) {
+	value, ok := unstructuredMemos.Load(reflect.ValueOf(objs).Pointer())
+	if !ok {
+		return unstructuredContentOf
+	}
+	memo := value.(*sync.Map)
+	return func(obj machinery.Object) (map[string]any, error) {
+		if content, ok := memo.Load(obj.GetLocator()); ok {
+			return content.(map[string]any), nil
+		}
+		content, err := unstructuredContentOf(obj)
+		if err != nil {
+			return nil, err
+		}
+		memo.Store(obj.GetLocator(), content)
+		return content, nil
+	}
+}
+
+// unstructuredContentOf returns the unstructured representation of an object of the topology, unwrapping
+// RuntimeObjects when needed
+func unstructuredContentOf(obj machinery.Object) (map[string]any, error) {
+	var o any = obj
+	if runtimeObj, ok := obj.(*RuntimeObject); ok {
+		o = runtimeObj.Object
+	}
+	if u, ok := o.(runtime.Unstructured); ok {
+		return u.UnstructuredContent(), nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(o)
+}
+
+// asMachineryObject returns the object itself if it implements the machinery.Object interface, otherwise the object
+// wrapped as a RuntimeObject
+func asMachineryObject(obj Object) machinery.Object {
+	if o, ok := obj.(machinery.Object); ok {
+		return o
+	}
+	return &RuntimeObject{obj}
+}
+
+func namespacedName(namespace, name string) string {
+	return k8stypes.NamespacedName{Namespace: namespace, Name: name}.String()
+}
@@ -0,0 +1,226 @@
+//go:build unit
+
+package controller
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kuadrant/policy-machinery/machinery"
+)
+
+func TestLinkFromFieldPath(t *testing.T) {
+	policyKind := schema.GroupKind{Group: "test", Kind: "TestPolicy"}
+
+	store := Store{
+		"gatewayclass-1": buildUnstructured("gateway.networking.k8s.io/v1", "GatewayClass", "", "gatewayclass-1", nil),
+		"gateway-1":      buildUnstructured("gateway.networking.k8s.io/v1", "Gateway", "default", "gateway-1", nil),
+		"gateway-2":      buildUnstructured("gateway.networking.k8s.io/v1", "Gateway", "default", "gateway-2", nil),
+		"gateway-3":      buildUnstructured("gateway.networking.k8s.io/v1", "Gateway", "other", "gateway-1", nil),
+		"service-1":      buildUnstructured("v1", "Service", "default", "gateway-1", nil),
+	}
+
+	testCases := []struct {
+		name            string
+		from            schema.GroupKind
+		to              schema.GroupKind
+		fieldPath       string
+		options         []FieldPathLinkOptionsFunc
+		child           machinery.Object
+		expectedParents []string
+	}{
+		{
+			name:      "single target reference",
+			from:      machinery.GatewayGroupKind,
+			to:        policyKind,
+			fieldPath: "spec.targetRef",
+			child: &RuntimeObject{buildUnstructured("test/v1", "TestPolicy", "default", "policy-1", map[string]any{
+				"targetRef": map[string]any{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "gateway-1"},
+			})},
+			expectedParents: []string{"gateway.gateway.networking.k8s.io:default/gateway-1"},
+		},
+		{
+			name:      "target reference to another kind",
+			from:      machinery.GatewayGroupKind,
+			to:        policyKind,
+			fieldPath: "spec.targetRef",
+			child: &RuntimeObject{buildUnstructured("test/v1", "TestPolicy", "default", "policy-1", map[string]any{
+				"targetRef": map[string]any{"group": "", "kind": "Service", "name": "gateway-1"},
+			})},
+		},
+		{
+			name:      "target reference to unknown object",
+			from:      machinery.GatewayGroupKind,
+			to:        policyKind,
+			fieldPath: "spec.targetRef",
+			child: &RuntimeObject{buildUnstructured("test/v1", "TestPolicy", "default", "policy-1", map[string]any{
+				"targetRef": map[string]any{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "unknown"},
+			})},
+		},
+		{
+			name:      "missing field path",
+			from:      machinery.GatewayGroupKind,
+			to:        policyKind,
+			fieldPath: "spec.targetRef",
+			child:     &RuntimeObject{buildUnstructured("test/v1", "TestPolicy", "default", "policy-1", map[string]any{})},
+		},
+		{
+			name:      "list of target references",
+			from:      machinery.GatewayGroupKind,
+			to:        policyKind,
+			fieldPath: "spec.targetRefs[*]",
+			child: &RuntimeObject{buildUnstructured("test/v1", "TestPolicy", "default", "policy-1", map[string]any{
+				"targetRefs": []any{
+					map[string]any{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "gateway-1"},
+					map[string]any{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "gateway-2"},
+					map[string]any{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "gateway-2"},
+				},
+			})},
+			expectedParents: []string{
+				"gateway.gateway.networking.k8s.io:default/gateway-1",
+				"gateway.gateway.networking.k8s.io:default/gateway-2",
+			},
+		},
+		{
+			name:      "parent references with defaults of a typed object",
+			from:      machinery.GatewayGroupKind,
+			to:        machinery.HTTPRouteGroupKind,
+			fieldPath: "spec.parentRefs[*]",
+			options:   []FieldPathLinkOptionsFunc{WithDefaultGroupKind(machinery.GatewayGroupKind)},
+			child: &machinery.HTTPRoute{HTTPRoute: machinery.BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+				r.Namespace = "default"
+				r.Spec.ParentRefs = []gwapiv1.ParentReference{
+					{Name: "gateway-1"},
+					{Name: "gateway-1", Namespace: ptr.To(gwapiv1.Namespace("other"))},
+					{Name: "gateway-2", Kind: ptr.To(gwapiv1.Kind("Service")), Group: ptr.To(gwapiv1.Group(""))},
+				}
+			})},
+			expectedParents: []string{
+				"gateway.gateway.networking.k8s.io:default/gateway-1",
+				"gateway.gateway.networking.k8s.io:other/gateway-1",
+			},
+		},
+		{
+			name:      "owner references",
+			from:      machinery.GatewayGroupKind,
+			to:        schema.GroupKind{Kind: "Service"},
+			fieldPath: "metadata.ownerReferences[*]",
+			options:   []FieldPathLinkOptionsFunc{WithAPIVersionField("apiVersion")},
+			child: &RuntimeObject{func() *unstructured.Unstructured {
+				u := buildUnstructured("v1", "Service", "default", "gateway-2-istio", nil)
+				u.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "gateway.networking.k8s.io/v1", Kind: "Gateway", Name: "gateway-2"}})
+				return u
+			}()},
+			expectedParents: []string{"gateway.gateway.networking.k8s.io:default/gateway-2"},
+		},
+		{
+			name:      "custom reference fields",
+			from:      machinery.GatewayGroupKind,
+			to:        policyKind,
+			fieldPath: "spec.selector",
+			options: []FieldPathLinkOptionsFunc{
+				WithGroupField("target.group"),
+				WithKindField("target.kind"),
+				WithNameField("target.name"),
+				WithNamespaceField("target.ns"),
+			},
+			child: &RuntimeObject{buildUnstructured("test/v1", "TestPolicy", "default", "policy-1", map[string]any{
+				"selector": map[string]any{
+					"target": map[string]any{"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "gateway-1", "ns": "other"},
+				},
+			})},
+			expectedParents: []string{"gateway.gateway.networking.k8s.io:other/gateway-1"},
+		},
+		{
+			name:      "name reference to cluster-scoped object",
+			from:      machinery.GatewayClassGroupKind,
+			to:        machinery.GatewayGroupKind,
+			fieldPath: "spec.gatewayClassName",
+			child: &machinery.Gateway{Gateway: machinery.BuildGateway(func(g *gwapiv1.Gateway) {
+				g.Spec.GatewayClassName = "gatewayclass-1"
+			})},
+			expectedParents: []string{"gatewayclass.gateway.networking.k8s.io:gatewayclass-1"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			link := LinkFromFieldPath(tc.from, tc.to, tc.fieldPath, tc.options...)(store)
+			if link.From != tc.from || link.To != tc.to {
+				t.Errorf("expected link from %s to %s, got from %s to %s", tc.from, tc.to, link.From, link.To)
+			}
+			parents := lo.Map(link.Func(tc.child), machinery.MapObjectToLocatorFunc)
+			slices.Sort(parents)
+			if !slices.Equal(parents, tc.expectedParents) {
+				t.Errorf("expected parents %v, got %v", tc.expectedParents, parents)
+			}
+		})
+	}
+}
+
+func buildUnstructured(apiVersion, kind, namespace, name string, spec map[string]any) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{Object: map[string]any{}}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	if spec != nil {
+		u.Object["spec"] = spec
+	}
+	return u
+}
+
+// BenchmarkLinkFromFieldPath benchmarks the link functions that read the parentRefs and the backendRefs of a large set
+// of typed HTTPRoutes, with the HTTPRoutes converted to unstructured by each link function or only once per store
+func BenchmarkLinkFromFieldPath(b *testing.B) {
+	const gateways, httpRoutes = 100, 10000
+
+	store := Store{}
+	for i := range gateways {
+		gateway := machinery.BuildGateway(func(g *gwapiv1.Gateway) { g.Name = fmt.Sprintf("gateway-%d", i) })
+		store[gateway.Name] = gateway
+	}
+	for i := range httpRoutes {
+		service := machinery.BuildService(func(s *corev1.Service) { s.Name = fmt.Sprintf("service-%d", i) })
+		store[service.Name] = service
+	}
+	children := lo.Times(httpRoutes, func(i int) machinery.Object {
+		return &machinery.HTTPRoute{HTTPRoute: machinery.BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Name = fmt.Sprintf("http-route-%d", i)
+			r.Spec.ParentRefs[0].Name = gwapiv1.ObjectName(fmt.Sprintf("gateway-%d", i%gateways))
+			r.Spec.Rules[0].BackendRefs[0].Name = gwapiv1.ObjectName(fmt.Sprintf("service-%d", i))
+		})}
+	})
+	links := []LinkFunc{
+		LinkFromFieldPath(machinery.GatewayGroupKind, machinery.HTTPRouteGroupKind, "spec.parentRefs[*]", WithDefaultGroupKind(machinery.GatewayGroupKind)),
+		LinkFromFieldPath(machinery.ServiceGroupKind, machinery.HTTPRouteGroupKind, "spec.rules[*].backendRefs[*]", WithDefaultGroupKind(machinery.ServiceGroupKind)),
+	}
+
+	benchmark := func(b *testing.B, memoize bool) {
+		b.ReportAllocs()
+		for b.Loop() {
+			release := func() {}
+			if memoize {
+				release = memoizeUnstructuredContent(store)
+			}
+			for _, link := range links {
+				f := link(store).Func
+				for _, child := range children {
+					f(child)
+				}
+			}
+			release()
+		}
+	}
+
+	b.Run("converted by each link", func(b *testing.B) { benchmark(b, false) })
+	b.Run("converted once per store", func(b *testing.B) { benchmark(b, true) })
+}
@@ -1,19 +1,53 @@
 package controller
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
 	core "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1alpha2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gwapiv1alpha3 "sigs.k8s.io/gateway-api/apis/v1alpha3"
+	gwapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 // API Resources
 var (
 	// core
+	NamespacesResource = core.SchemeGroupVersion.WithResource("namespaces")
 	ServicesResource   = core.SchemeGroupVersion.WithResource("services")
 	ConfigMapsResource = core.SchemeGroupVersion.WithResource("configmaps")
 
+	// discovery
+	EndpointSlicesResource = discovery.SchemeGroupVersion.WithResource("endpointslices")
+
+	// apps
+	DeploymentsResource = appsv1.SchemeGroupVersion.WithResource("deployments")
+
+	// autoscaling
+	HorizontalPodAutoscalersResource = autoscalingv2.SchemeGroupVersion.WithResource("horizontalpodautoscalers")
+
 	// gateway api
-	GatewayClassesResource = gwapiv1.SchemeGroupVersion.WithResource("gatewayclasses")
-	GatewaysResource       = gwapiv1.SchemeGroupVersion.WithResource("gateways")
-	GRPCRoutesResource     = gwapiv1.SchemeGroupVersion.WithResource("grpcroutes")
-	HTTPRoutesResource     = gwapiv1.SchemeGroupVersion.WithResource("httproutes")
+	GatewayClassesResource     = gwapiv1.SchemeGroupVersion.WithResource("gatewayclasses")
+	GatewaysResource           = gwapiv1.SchemeGroupVersion.WithResource("gateways")
+	ListenerSetsResource       = gwapiv1.SchemeGroupVersion.WithResource("listenersets")
+	GRPCRoutesResource         = gwapiv1.SchemeGroupVersion.WithResource("grpcroutes")
+	HTTPRoutesResource         = gwapiv1.SchemeGroupVersion.WithResource("httproutes")
+	TCPRoutesResource          = gwapiv1.SchemeGroupVersion.WithResource("tcproutes")
+	TLSRoutesResource          = gwapiv1.SchemeGroupVersion.WithResource("tlsroutes")
+	UDPRoutesResource          = gwapiv1.SchemeGroupVersion.WithResource("udproutes")
+	ReferenceGrantsResource    = gwapiv1beta1.SchemeGroupVersion.WithResource("referencegrants")
+	BackendTLSPoliciesResource = gwapiv1.SchemeGroupVersion.WithResource("backendtlspolicies")
+
+	// gateway api (older versions)
+	// Watch these with WatchGatewayAPIResource to normalize the objects into the v1 types.
+	GatewayClassesV1beta1Resource      = gwapiv1beta1.SchemeGroupVersion.WithResource("gatewayclasses")
+	GatewaysV1beta1Resource            = gwapiv1beta1.SchemeGroupVersion.WithResource("gateways")
+	HTTPRoutesV1beta1Resource          = gwapiv1beta1.SchemeGroupVersion.WithResource("httproutes")
+	GRPCRoutesV1alpha2Resource         = gwapiv1alpha2.SchemeGroupVersion.WithResource("grpcroutes")
+	TCPRoutesV1alpha2Resource          = gwapiv1alpha2.SchemeGroupVersion.WithResource("tcproutes")
+	TLSRoutesV1alpha2Resource          = gwapiv1alpha2.SchemeGroupVersion.WithResource("tlsroutes")
+	UDPRoutesV1alpha2Resource          = gwapiv1alpha2.SchemeGroupVersion.WithResource("udproutes")
+	ReferenceGrantsV1alpha2Resource    = gwapiv1alpha2.SchemeGroupVersion.WithResource("referencegrants")
+	BackendTLSPoliciesV1alpha3Resource = gwapiv1alpha3.SchemeGroupVersion.WithResource("backendtlspolicies")
 )
@@ -1,35 +1,132 @@
 package controller
 
 import (
+	"fmt"
+
 	"github.com/samber/lo"
 	core "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/kuadrant/policy-machinery/machinery"
 )
 
-func newGatewayAPITopologyBuilder(policyKinds, objectKinds []schema.GroupKind, objectLinks []LinkFunc, allowTopologyLoops bool) *gatewayAPITopologyBuilder {
+// TopologyExpansion is an expansion of the Gateway API resources into their targetable sections in the topology built
+// by the controller. Each expansion stands for the option of the same name of the machinery package.
+type TopologyExpansion string
+
+const (
+	ExpandGatewayListeners TopologyExpansion = "GatewayListeners"
+	ExpandHostnames        TopologyExpansion = "Hostnames"
+	ExpandHTTPRouteRules   TopologyExpansion = "HTTPRouteRules"
+	ExpandHTTPRouteMatches TopologyExpansion = "HTTPRouteMatches"
+	ExpandGRPCRouteRules   TopologyExpansion = "GRPCRouteRules"
+	ExpandGRPCRouteMatches TopologyExpansion = "GRPCRouteMatches"
+	ExpandTCPRouteRules    TopologyExpansion = "TCPRouteRules"
+	ExpandTLSRouteRules    TopologyExpansion = "TLSRouteRules"
+	ExpandUDPRouteRules    TopologyExpansion = "UDPRouteRules"
+	ExpandServicePorts     TopologyExpansion = "ServicePorts"
+	ExpandEndpointZones    TopologyExpansion = "EndpointZones"
+)
+
+// topologyExpansionOptions are the options of the Gateway API topology for each supported expansion
+var topologyExpansionOptions = map[TopologyExpansion]machinery.GatewayAPITopologyOptionsFunc{
+	ExpandGatewayListeners: machinery.ExpandGatewayListeners(),
+	ExpandHostnames:        machinery.ExpandHostnames(),
+	ExpandHTTPRouteRules:   machinery.ExpandHTTPRouteRules(),
+	ExpandHTTPRouteMatches: machinery.ExpandHTTPRouteMatches(),
+	ExpandGRPCRouteRules:   machinery.ExpandGRPCRouteRules(),
+	ExpandGRPCRouteMatches: machinery.ExpandGRPCRouteMatches(),
+	ExpandTCPRouteRules:    machinery.ExpandTCPRouteRules(),
+	ExpandTLSRouteRules:    machinery.ExpandTLSRouteRules(),
+	ExpandUDPRouteRules:    machinery.ExpandUDPRouteRules(),
+	ExpandServicePorts:     machinery.ExpandServicePorts(),
+	ExpandEndpointZones:    machinery.ExpandEndpointZones(),
+}
+
+// defaultTopologyExpansions are the expansions applied to the topology when no expansions are chosen
+var defaultTopologyExpansions = []TopologyExpansion{
+	ExpandGatewayListeners,
+	ExpandHTTPRouteRules,
+	ExpandGRPCRouteRules,
+	ExpandServicePorts,
+}
+
+// routeKindOptions are the functions that add the routes of each supported kind to the options of the topology
+var routeKindOptions = map[schema.GroupKind]func(routes []Object) machinery.GatewayAPITopologyOptionsFunc{
+	machinery.HTTPRouteGroupKind: func(routes []Object) machinery.GatewayAPITopologyOptionsFunc {
+		return machinery.WithHTTPRoutes(lo.Map(routes, ObjectAs[*gwapiv1.HTTPRoute])...)
+	},
+	machinery.GRPCRouteGroupKind: func(routes []Object) machinery.GatewayAPITopologyOptionsFunc {
+		return machinery.WithGRPCRoutes(lo.Map(routes, ObjectAs[*gwapiv1.GRPCRoute])...)
+	},
+	machinery.TCPRouteGroupKind: func(routes []Object) machinery.GatewayAPITopologyOptionsFunc {
+		return machinery.WithTCPRoutes(lo.Map(routes, ObjectAs[*gwapiv1.TCPRoute])...)
+	},
+	machinery.TLSRouteGroupKind: func(routes []Object) machinery.GatewayAPITopologyOptionsFunc {
+		return machinery.WithTLSRoutes(lo.Map(routes, ObjectAs[*gwapiv1.TLSRoute])...)
+	},
+	machinery.UDPRouteGroupKind: func(routes []Object) machinery.GatewayAPITopologyOptionsFunc {
+		return machinery.WithUDPRoutes(lo.Map(routes, ObjectAs[*gwapiv1.UDPRoute])...)
+	},
+}
+
+// defaultRouteKinds are the kinds of routes included in the topology when no route kinds are chosen
+var defaultRouteKinds = []schema.GroupKind{
+	machinery.HTTPRouteGroupKind,
+	machinery.GRPCRouteGroupKind,
+}
+
+func newGatewayAPITopologyBuilder(opts *ControllerOptions) *gatewayAPITopologyBuilder {
+	routeKinds := opts.routeKinds
+	if routeKinds == nil {
+		routeKinds = defaultRouteKinds
+	}
+	expansions := opts.topologyExpansions
+	if expansions == nil {
+		expansions = defaultTopologyExpansions
+	}
 	return &gatewayAPITopologyBuilder{
-		policyKinds:        policyKinds,
-		objectKinds:        objectKinds,
-		objectLinks:        objectLinks,
-		allowTopologyLoops: allowTopologyLoops,
+		policyKinds:            opts.policyKinds,
+		objectKinds:            opts.objectKinds,
+		objectLinks:            opts.objectLinks,
+		allowTopologyLoops:     opts.allowTopologyLoops,
+		parallelism:            opts.topologyParallelism,
+		gatewayControllerNames: opts.gatewayControllerNames,
+		routeKinds:             routeKinds,
+		expansions:             expansions,
+		namespaces:             opts.topologyNamespaces,
+		listenerSets:           opts.topologyListenerSets,
+		referenceGrants:        opts.topologyRefGrants,
+		endpointSlices:         opts.topologyEndpointSlices,
+		requireReferenceGrants: opts.requireReferenceGrants,
 	}
 }
 
 type gatewayAPITopologyBuilder struct {
-	policyKinds        []schema.GroupKind
-	objectKinds        []schema.GroupKind
-	objectLinks        []LinkFunc
-	allowTopologyLoops bool
+	policyKinds            []schema.GroupKind
+	objectKinds            []schema.GroupKind
+	objectLinks            []LinkFunc
+	allowTopologyLoops     bool
+	parallelism            int
+	gatewayControllerNames []gwapiv1.GatewayController
+	routeKinds             []schema.GroupKind
+	expansions             []TopologyExpansion
+	namespaces             bool
+	listenerSets           bool
+	referenceGrants        bool
+	endpointSlices         bool
+	requireReferenceGrants bool
 }
 
 func (t *gatewayAPITopologyBuilder) Build(objs Store) (*machinery.Topology, error) {
+	// objects read by multiple link functions are converted to unstructured only once
+	defer memoizeUnstructuredContent(objs)()
+
 	gatewayClasses := lo.Map(objs.FilterByGroupKind(machinery.GatewayClassGroupKind), ObjectAs[*gwapiv1.GatewayClass])
 	gateways := lo.Map(objs.FilterByGroupKind(machinery.GatewayGroupKind), ObjectAs[*gwapiv1.Gateway])
-	httpRoutes := lo.Map(objs.FilterByGroupKind(machinery.HTTPRouteGroupKind), ObjectAs[*gwapiv1.HTTPRoute])
-	grpcRoutes := lo.Map(objs.FilterByGroupKind(machinery.GRPCRouteGroupKind), ObjectAs[*gwapiv1.GRPCRoute])
 	services := lo.Map(objs.FilterByGroupKind(machinery.ServiceGroupKind), ObjectAs[*core.Service])
 
 	linkFuncs := lo.Map(t.objectLinks, func(f LinkFunc, _ int) machinery.LinkFunc {
@@ -39,20 +136,55 @@ func (t *gatewayAPITopologyBuilder) Build(objs Store) (*machinery.Topology, erro
 	opts := []machinery.GatewayAPITopologyOptionsFunc{
 		machinery.WithGatewayClasses(gatewayClasses...),
 		machinery.WithGateways(gateways...),
-		machinery.WithHTTPRoutes(httpRoutes...),
-		machinery.WithGRPCRoutes(grpcRoutes...),
 		machinery.WithServices(services...),
-		machinery.ExpandGatewayListeners(),
-		machinery.ExpandHTTPRouteRules(),
-		machinery.ExpandGRPCRouteRules(),
-		machinery.ExpandServicePorts(),
 		machinery.WithGatewayAPITopologyLinks(linkFuncs...),
+		machinery.WithGatewayAPITopologyParallelism(t.parallelism),
+	}
+
+	if t.namespaces {
+		opts = append(opts, machinery.WithNamespaces(lo.Map(objs.FilterByGroupKind(machinery.NamespaceGroupKind), ObjectAs[*core.Namespace])...))
+	}
+
+	if t.listenerSets {
+		opts = append(opts, machinery.WithListenerSets(lo.Map(objs.FilterByGroupKind(machinery.ListenerSetGroupKind), ObjectAs[*gwapiv1.ListenerSet])...))
+	}
+
+	if t.referenceGrants {
+		opts = append(opts, machinery.WithReferenceGrants(lo.Map(objs.FilterByGroupKind(machinery.ReferenceGrantGroupKind), ObjectAs[*gwapiv1beta1.ReferenceGrant])...))
+	}
+
+	if t.requireReferenceGrants {
+		opts = append(opts, machinery.RequireReferenceGrants())
+	}
+
+	if t.endpointSlices {
+		opts = append(opts, machinery.WithEndpointSlices(lo.Map(objs.FilterByGroupKind(machinery.EndpointSliceGroupKind), ObjectAs[*discovery.EndpointSlice])...))
+	}
+
+	for _, routeKind := range t.routeKinds {
+		routeKindOption, ok := routeKindOptions[routeKind]
+		if !ok {
+			return nil, fmt.Errorf("unsupported route kind: %s", routeKind)
+		}
+		opts = append(opts, routeKindOption(objs.FilterByGroupKind(routeKind)))
+	}
+
+	for _, expansion := range t.expansions {
+		expansionOption, ok := topologyExpansionOptions[expansion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported topology expansion: %s", expansion)
+		}
+		opts = append(opts, expansionOption)
 	}
 
 	if t.allowTopologyLoops {
 		opts = append(opts, machinery.AllowTopologyLoops())
 	}
 
+	if len(t.gatewayControllerNames) > 0 {
+		opts = append(opts, machinery.WithGatewayControllerNames(t.gatewayControllerNames...))
+	}
+
 	for i := range t.policyKinds {
 		policyKind := t.policyKinds[i]
 		policies := lo.Map(objs.FilterByGroupKind(policyKind), ObjectAs[machinery.Policy])
@@ -61,12 +193,8 @@ func (t *gatewayAPITopologyBuilder) Build(objs Store) (*machinery.Topology, erro
 
 	for i := range t.objectKinds {
 		objectKind := t.objectKinds[i]
-		objects := lo.FilterMap(objs.FilterByGroupKind(objectKind), func(obj Object, _ int) (machinery.Object, bool) {
-			object, ok := obj.(machinery.Object)
-			if ok {
-				return object, ok
-			}
-			return &RuntimeObject{obj}, true
+		objects := lo.Map(objs.FilterByGroupKind(objectKind), func(obj Object, _ int) machinery.Object {
+			return asMachineryObject(obj)
 		})
 		opts = append(opts, machinery.WithGatewayAPITopologyObjects(objects...))
 	}
@@ -7,9 +7,12 @@ import (
 
 	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	"github.com/kuadrant/policy-machinery/machinery"
 )
@@ -22,6 +25,10 @@ func TestGatewayAPITopologyBuilder(t *testing.T) {
 		expectedHTTPRoutes int
 		expectedGRPCRoutes int
 		expectedServices   int
+		expectedNamespaces int
+		expectedSlices     int
+		controllerNames    []gwapiv1.GatewayController
+		options            []ControllerOption
 	}{
 		{
 			name:               "empty store",
@@ -100,6 +107,70 @@ func TestGatewayAPITopologyBuilder(t *testing.T) {
 			expectedGRPCRoutes: 1,
 			expectedServices:   1,
 		},
+		{
+			name: "namespaces",
+			objects: []Object{
+				machinery.BuildNamespace(func(ns *corev1.Namespace) {
+					ns.UID = types.UID("namespace-1")
+				}),
+				machinery.BuildNamespace(func(ns *corev1.Namespace) {
+					ns.Name = "app"
+					ns.UID = types.UID("namespace-2")
+				}),
+				machinery.BuildGateway(func(g *gwapiv1.Gateway) {
+					g.UID = types.UID("gateway-1")
+				}),
+			},
+			options:            []ControllerOption{WithTopologyNamespaces()},
+			expectedGateways:   1,
+			expectedNamespaces: 2,
+		},
+		{
+			name: "endpoint slices",
+			objects: []Object{
+				machinery.BuildService(func(s *corev1.Service) {
+					s.UID = types.UID("service-1")
+				}),
+				machinery.BuildEndpointSlice(func(e *discoveryv1.EndpointSlice) {
+					e.UID = types.UID("endpointslice-1")
+				}),
+			},
+			options:          []ControllerOption{WithTopologyEndpointSlices()},
+			expectedServices: 1,
+			expectedSlices:   1,
+		},
+		{
+			name: "gateway controller names",
+			objects: []Object{
+				machinery.BuildGatewayClass(func(gc *gwapiv1.GatewayClass) {
+					gc.UID = types.UID("gatewayclass-1")
+				}),
+				machinery.BuildGatewayClass(func(gc *gwapiv1.GatewayClass) {
+					gc.Name = "other-gateway-class"
+					gc.Spec.ControllerName = "other-controller"
+					gc.UID = types.UID("gatewayclass-2")
+				}),
+				machinery.BuildGateway(func(g *gwapiv1.Gateway) {
+					g.UID = types.UID("gateway-1")
+				}),
+				machinery.BuildGateway(func(g *gwapiv1.Gateway) {
+					g.Name = "other-gateway"
+					g.Spec.GatewayClassName = "other-gateway-class"
+					g.UID = types.UID("gateway-2")
+				}),
+				machinery.BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+					r.UID = types.UID("httproute-1")
+				}),
+				machinery.BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+					r.Name = "other-http-route"
+					r.Spec.ParentRefs[0].Name = "other-gateway"
+					r.UID = types.UID("httproute-2")
+				}),
+			},
+			controllerNames:    []gwapiv1.GatewayController{"my-gateway-controller"},
+			expectedGateways:   1,
+			expectedHTTPRoutes: 1,
+		},
 		{
 			name: "multiple grpcroutes",
 			objects: []Object{
@@ -131,7 +202,11 @@ func TestGatewayAPITopologyBuilder(t *testing.T) {
 				store[string(obj.GetUID())] = obj
 			}
 
-			builder := newGatewayAPITopologyBuilder(nil, nil, nil, false)
+			opts := &ControllerOptions{gatewayControllerNames: tc.controllerNames}
+			for _, f := range tc.options {
+				f(opts)
+			}
+			builder := newGatewayAPITopologyBuilder(opts)
 			topology, err := builder.Build(store)
 
 			if err != nil {
@@ -156,6 +231,12 @@ func TestGatewayAPITopologyBuilder(t *testing.T) {
 			services := lo.Filter(targetables, func(obj machinery.Targetable, _ int) bool {
 				return obj.GroupVersionKind().Kind == "Service"
 			})
+			namespaces := lo.Filter(targetables, func(obj machinery.Targetable, _ int) bool {
+				return obj.GroupVersionKind().Kind == "Namespace"
+			})
+			endpointSlices := lo.Filter(targetables, func(obj machinery.Targetable, _ int) bool {
+				return obj.GroupVersionKind().Kind == "EndpointSlice"
+			})
 
 			if len(gateways) != tc.expectedGateways {
 				t.Errorf("expected %d gateways in topology, got %d", tc.expectedGateways, len(gateways))
@@ -169,6 +250,135 @@ func TestGatewayAPITopologyBuilder(t *testing.T) {
 			if len(services) != tc.expectedServices {
 				t.Errorf("expected %d services in topology, got %d", tc.expectedServices, len(services))
 			}
+			if len(namespaces) != tc.expectedNamespaces {
+				t.Errorf("expected %d namespaces in topology, got %d", tc.expectedNamespaces, len(namespaces))
+			}
+			if len(endpointSlices) != tc.expectedSlices {
+				t.Errorf("expected %d endpointslices in topology, got %d", tc.expectedSlices, len(endpointSlices))
+			}
+		})
+	}
+}
+
+// TestGatewayAPITopologyBuilder_BaselineOptions tests that Namespaces, ListenerSets, ReferenceGrants and EndpointSlices
+// in the store are left out of the topology unless the controller options include them.
+func TestGatewayAPITopologyBuilder_BaselineOptions(t *testing.T) {
+	gatewayClass := machinery.BuildGatewayClass(func(gc *gwapiv1.GatewayClass) { gc.UID = types.UID("gatewayclass-1") })
+	gateway := machinery.BuildGateway(func(g *gwapiv1.Gateway) { g.UID = types.UID("gateway-1") })
+	httpRoute := machinery.BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) { r.UID = types.UID("httproute-1") })
+	grpcRoute := machinery.BuildGRPCRoute(func(r *gwapiv1.GRPCRoute) { r.UID = types.UID("grpcroute-1") })
+	service := machinery.BuildService(func(s *corev1.Service) { s.UID = types.UID("service-1") })
+
+	store := Store{}
+	for _, obj := range []Object{
+		gatewayClass,
+		gateway,
+		httpRoute,
+		grpcRoute,
+		service,
+		machinery.BuildNamespace(func(ns *corev1.Namespace) { ns.UID = types.UID("namespace-1") }),
+		machinery.BuildListenerSet(func(l *gwapiv1.ListenerSet) { l.UID = types.UID("listenerset-1") }),
+		machinery.BuildReferenceGrant(func(g *gwapiv1beta1.ReferenceGrant) { g.UID = types.UID("referencegrant-1") }),
+		machinery.BuildEndpointSlice(func(e *discoveryv1.EndpointSlice) { e.UID = types.UID("endpointslice-1") }),
+	} {
+		store[string(obj.GetUID())] = obj
+	}
+
+	topology, err := newGatewayAPITopologyBuilder(&ControllerOptions{}).Build(store)
+	if err != nil {
+		t.Fatalf("unexpected error building topology: %v", err)
+	}
+
+	expected, err := machinery.NewGatewayAPITopology(
+		machinery.WithGatewayClasses(gatewayClass),
+		machinery.WithGateways(gateway),
+		machinery.WithHTTPRoutes(httpRoute),
+		machinery.WithGRPCRoutes(grpcRoute),
+		machinery.WithServices(service),
+		machinery.ExpandGatewayListeners(),
+		machinery.ExpandHTTPRouteRules(),
+		machinery.ExpandGRPCRouteRules(),
+		machinery.ExpandServicePorts(),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error building the expected topology: %v", err)
+	}
+
+	if topology.ToDot() != expected.ToDot() {
+		t.Errorf("expected topology:\n%s\ngot:\n%s", expected.ToDot(), topology.ToDot())
+	}
+	if objects := topology.Objects().Items(); len(objects) != 0 {
+		t.Errorf("expected no objects in topology, got %d", len(objects))
+	}
+}
+
+func TestGatewayAPITopologyBuilder_RequireReferenceGrants(t *testing.T) {
+	store := Store{}
+	for _, obj := range []Object{
+		machinery.BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.UID = types.UID("httproute-1")
+			r.Namespace = "other-namespace"
+			r.Spec.Rules[0].BackendRefs[0].Namespace = ptr.To(gwapiv1.Namespace("my-namespace"))
+		}),
+		machinery.BuildService(func(s *corev1.Service) { s.UID = types.UID("service-1") }),
+	} {
+		store[string(obj.GetUID())] = obj
+	}
+
+	testCases := []struct {
+		name                 string
+		options              []ControllerOption
+		referenceGrant       bool
+		expectedServiceLinks int
+	}{
+		{
+			name:                 "not required",
+			expectedServiceLinks: 1,
+		},
+		{
+			name:                 "required without reference grant",
+			options:              []ControllerOption{RequireReferenceGrants()},
+			expectedServiceLinks: 0,
+		},
+		{
+			name:                 "required with reference grant",
+			options:              []ControllerOption{RequireReferenceGrants()},
+			referenceGrant:       true,
+			expectedServiceLinks: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			objs := Store{}
+			for id, obj := range store {
+				objs[id] = obj
+			}
+			if tc.referenceGrant {
+				objs["referencegrant-1"] = machinery.BuildReferenceGrant(func(g *gwapiv1beta1.ReferenceGrant) { g.UID = types.UID("referencegrant-1") })
+			}
+
+			opts := &ControllerOptions{}
+			for _, f := range tc.options {
+				f(opts)
+			}
+			topology, err := newGatewayAPITopologyBuilder(opts).Build(objs)
+			if err != nil {
+				t.Fatalf("unexpected error building topology: %v", err)
+			}
+
+			service, found := lo.Find(topology.Targetables().Items(), func(obj machinery.Targetable) bool {
+				return obj.GroupVersionKind().GroupKind() == machinery.ServiceGroupKind
+			})
+			if !found {
+				t.Fatal("expected service in topology")
+			}
+			parents := lo.Filter(topology.Targetables().Parents(service), func(obj machinery.Targetable, _ int) bool {
+				return obj.GroupVersionKind().GroupKind() == machinery.HTTPRouteRuleGroupKind
+			})
+			if len(parents) != tc.expectedServiceLinks {
+				t.Errorf("expected %d links from http route rules to the service, got %d", tc.expectedServiceLinks, len(parents))
+			}
 		})
 	}
 }
@@ -208,7 +418,7 @@ func TestGatewayAPITopologyBuilder_GRPCRouteWithMultipleRules(t *testing.T) {
 		string(grpcRoute.GetUID()): grpcRoute,
 	}
 
-	builder := newGatewayAPITopologyBuilder(nil, nil, nil, false)
+	builder := newGatewayAPITopologyBuilder(&ControllerOptions{})
 	topology, err := builder.Build(store)
 
 	if err != nil {
@@ -238,3 +448,145 @@ func TestGatewayAPITopologyBuilder_GRPCRouteWithMultipleRules(t *testing.T) {
 	// which is tested in machinery/gateway_api_topology_test.go.
 	// Here we verify that the controller layer correctly calls ExpandGRPCRouteRules().
 }
+
+func TestGatewayAPITopologyBuilder_RouteKindsAndExpansions(t *testing.T) {
+	store := Store{}
+	for _, obj := range []Object{
+		machinery.BuildGateway(func(g *gwapiv1.Gateway) { g.UID = types.UID("gateway-1") }),
+		machinery.BuildListenerSet(func(l *gwapiv1.ListenerSet) { l.UID = types.UID("listenerset-1") }),
+		machinery.BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) { r.UID = types.UID("httproute-1") }),
+		machinery.BuildTCPRoute(func(r *gwapiv1.TCPRoute) { r.UID = types.UID("tcproute-1") }),
+		machinery.BuildTLSRoute(func(r *gwapiv1.TLSRoute) { r.UID = types.UID("tlsroute-1") }),
+		machinery.BuildUDPRoute(func(r *gwapiv1.UDPRoute) { r.UID = types.UID("udproute-1") }),
+		machinery.BuildService(func(s *corev1.Service) { s.UID = types.UID("service-1") }),
+		machinery.BuildReferenceGrant(func(g *gwapiv1beta1.ReferenceGrant) { g.UID = types.UID("referencegrant-1") }),
+	} {
+		store[string(obj.GetUID())] = obj
+	}
+
+	testCases := []struct {
+		name       string
+		routeKinds []schema.GroupKind
+		expansions []TopologyExpansion
+		expected   map[string]int
+	}{
+		{
+			name: "defaults",
+			expected: map[string]int{
+				"ListenerSet":   1,
+				"Listener":      2,
+				"HTTPRoute":     1,
+				"HTTPRouteRule": 1,
+				"TCPRoute":      0,
+				"TLSRoute":      0,
+				"UDPRoute":      0,
+				"ServicePort":   1,
+			},
+		},
+		{
+			name:       "tcp and udp routes",
+			routeKinds: []schema.GroupKind{machinery.TCPRouteGroupKind, machinery.UDPRouteGroupKind},
+			expected: map[string]int{
+				"HTTPRoute":    0,
+				"TCPRoute":     1,
+				"TCPRouteRule": 0,
+				"TLSRoute":     0,
+				"UDPRoute":     1,
+			},
+		},
+		{
+			name:       "all route kinds with rules expanded",
+			routeKinds: []schema.GroupKind{machinery.HTTPRouteGroupKind, machinery.GRPCRouteGroupKind, machinery.TCPRouteGroupKind, machinery.TLSRouteGroupKind, machinery.UDPRouteGroupKind},
+			expansions: []TopologyExpansion{ExpandTCPRouteRules, ExpandTLSRouteRules, ExpandUDPRouteRules},
+			expected: map[string]int{
+				"Listener":      0,
+				"HTTPRoute":     1,
+				"HTTPRouteRule": 0,
+				"TCPRoute":      1,
+				"TCPRouteRule":  1,
+				"TLSRoute":      1,
+				"TLSRouteRule":  1,
+				"UDPRoute":      1,
+				"UDPRouteRule":  1,
+				"ServicePort":   0,
+			},
+		},
+		{
+			name:       "no expansions",
+			expansions: []TopologyExpansion{},
+			expected: map[string]int{
+				"Listener":      0,
+				"HTTPRoute":     1,
+				"HTTPRouteRule": 0,
+				"ServicePort":   0,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := &ControllerOptions{}
+			WithTopologyListenerSets()(opts)
+			WithTopologyReferenceGrants()(opts)
+			if tc.routeKinds != nil {
+				WithRouteKinds(tc.routeKinds...)(opts)
+			}
+			if tc.expansions != nil {
+				WithTopologyExpansions(tc.expansions...)(opts)
+			}
+			builder := newGatewayAPITopologyBuilder(opts)
+			topology, err := builder.Build(store)
+			if err != nil {
+				t.Fatalf("unexpected error building topology: %v", err)
+			}
+
+			counts := lo.CountValuesBy(topology.Targetables().Items(), func(obj machinery.Targetable) string {
+				return obj.GroupVersionKind().Kind
+			})
+			for kind, expected := range tc.expected {
+				if counts[kind] != expected {
+					t.Errorf("expected %d %s objects in topology, got %d", expected, kind, counts[kind])
+				}
+			}
+
+			referenceGrants := lo.Filter(topology.Objects().Items(), func(obj machinery.Object, _ int) bool {
+				return obj.GroupVersionKind().GroupKind() == machinery.ReferenceGrantGroupKind
+			})
+			if len(referenceGrants) != 1 {
+				t.Errorf("expected 1 referencegrant in topology, got %d", len(referenceGrants))
+			}
+		})
+	}
+}
+
+func TestGatewayAPITopologyBuilder_UnsupportedRouteKindsAndExpansions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		options       []ControllerOption
+		expectedError string
+	}{
+		{
+			name:          "unsupported route kind",
+			options:       []ControllerOption{WithRouteKinds(machinery.HTTPRouteGroupKind, machinery.ServiceGroupKind)},
+			expectedError: "unsupported route kind: Service",
+		},
+		{
+			name:          "unsupported expansion",
+			options:       []ControllerOption{WithTopologyExpansions(ExpandGatewayListeners, TopologyExpansion("IngressRules"))},
+			expectedError: "unsupported topology expansion: IngressRules",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := &ControllerOptions{}
+			for _, f := range tc.options {
+				f(opts)
+			}
+			_, err := newGatewayAPITopologyBuilder(opts).Build(Store{})
+			if err == nil || err.Error() != tc.expectedError {
+				t.Errorf("expected error %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
@@ -166,7 +166,7 @@ type DNSPolicy struct {
 	Status DNSPolicyStatus `json:"status,omitempty"`
 }
 
-var _ machinery.Policy = &DNSPolicy{}
+var _ machinery.PolicyWithAllowedTargets = &DNSPolicy{}
 
 func (p *DNSPolicy) GetTargetRefs() []machinery.PolicyTargetReference {
 	return []machinery.PolicyTargetReference{
@@ -177,6 +177,15 @@ func (p *DNSPolicy) GetTargetRefs() []machinery.PolicyTargetReference {
 	}
 }
 
+// AllowedTargetKinds mirrors the validation of the `targetRef.kind` field of the CRD
+func (p *DNSPolicy) AllowedTargetKinds() []schema.GroupKind {
+	return []schema.GroupKind{machinery.GatewayGroupKind}
+}
+
+func (p *DNSPolicy) AllowedSectionKinds() []schema.GroupKind {
+	return []schema.GroupKind{machinery.ListenerGroupKind}
+}
+
 func (p *DNSPolicy) GetMergeStrategy() machinery.MergeStrategy {
 	return func(policy machinery.Policy, _ machinery.Policy) machinery.Policy {
 		return policy
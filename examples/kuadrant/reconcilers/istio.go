@@ -3,7 +3,6 @@ package reconcilers
 import (
 	"context"
 	"fmt"
-	"strings"
 	"sync"
 
 	"github.com/samber/lo"
@@ -16,7 +15,6 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/utils/ptr"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/kuadrant/policy-machinery/controller"
@@ -151,10 +149,13 @@ func (p *IstioGatewayProvider) createAuthorizationPolicy(ctx context.Context, to
 		listener := path[1].(*machinery.Listener)
 		httpRoute := path[2].(*machinery.HTTPRoute)
 		routeRule := path[3].(*machinery.HTTPRouteRule)
-		hostname := ptr.Deref(listener.Hostname, gwapiv1.Hostname("*"))
-		hostnames := []gwapiv1.Hostname{hostname}
-		if len(httpRoute.Spec.Hostnames) > 0 {
-			hostnames = lo.Filter(httpRoute.Spec.Hostnames, hostSubsetOf(hostname))
+		hostnames, ok := machinery.EffectiveHostnames(listener, httpRoute)
+		if !ok {
+			// the hostnames of the listener and the route do not intersect
+			continue
+		}
+		if len(hostnames) == 0 {
+			hostnames = []gwapiv1.Hostname{"*"}
 		}
 		rules := istioAuthorizationPolicyRulesFromHTTPRouteRule(routeRule.HTTPRouteRule, hostnames)
 		desiredAuthorizationPolicy.Spec.Rules = append(desiredAuthorizationPolicy.Spec.Rules, rules...)
@@ -355,35 +356,6 @@ func istioAuthorizationPolicyRulesFromHTTPRouteRule(rule *gwapiv1.HTTPRouteRule,
 	return
 }
 
-// TODO: move this to a shared package
-func hostSubsetOf(superset gwapiv1.Hostname) func(gwapiv1.Hostname, int) bool {
-	wildcarded := func(hostname gwapiv1.Hostname) bool {
-		return len(hostname) > 0 && hostname[0] == '*'
-	}
-
-	return func(hostname gwapiv1.Hostname, _ int) bool {
-		if wildcarded(hostname) {
-			if wildcarded(superset) {
-				// both hostname and superset contain wildcard
-				if len(hostname) < len(superset) {
-					return false
-				}
-				return strings.HasSuffix(string(hostname[1:]), string(superset[1:]))
-			}
-			// only hostname contains wildcard
-			return false
-		}
-
-		if wildcarded(superset) {
-			// only superset contains wildcard
-			return strings.HasSuffix(string(hostname), string(superset[1:]))
-		}
-
-		// neither contains wildcard, so do normal string comparison
-		return hostname == superset
-	}
-}
-
 func LinkGatewayToIstioAuthorizationPolicyFunc(objs controller.Store) machinery.LinkFunc {
 	gateways := lo.Map(objs.FilterByGroupKind(machinery.GatewayGroupKind), controller.ObjectAs[*gwapiv1.Gateway])
 
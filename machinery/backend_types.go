@@ -0,0 +1,66 @@
+package machinery
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// BackendKind registers a kind of backend other than core Services that routes can refer to in their backendRefs,
+// e.g. multicluster ServiceImports or Gateway API Inference Extension InferencePools.
+// The backends are added to the topology as targetables and linked from the routes, or from the route rules when the
+// rules are expanded, that refer to them.
+type BackendKind struct {
+	// GroupKind is the group and kind of the backends, as referred in the `group` and `kind` fields of the backendRefs.
+	GroupKind schema.GroupKind
+
+	// Backends are the objects of the kind, wrapped as targetables.
+	Backends []Targetable
+
+	// Ports optionally expands a backend into its targetable ports. When set, backendRefs that specify a port are
+	// linked to the port of the backend, and only backendRefs that do not specify a port are linked to the backend
+	// itself, as with the Services when the service ports are expanded.
+	Ports func(backend Targetable) []*BackendPort
+}
+
+// PortGroupKind returns the group and kind of the targetable ports of the backends of the kind.
+func (k BackendKind) PortGroupKind() schema.GroupKind {
+	return schema.GroupKind{Group: k.GroupKind.Group, Kind: k.GroupKind.Kind + "Port"}
+}
+
+// BackendPort is a targetable port of a backend of a registered BackendKind.
+type BackendPort struct {
+	Backend Targetable
+	Kind    BackendKind
+	Name    gwapiv1.SectionName
+	Port    int32
+
+	attachedPolicies []Policy
+}
+
+var _ Targetable = &BackendPort{}
+
+func (p *BackendPort) GroupVersionKind() schema.GroupVersionKind {
+	return p.Kind.PortGroupKind().WithVersion("")
+}
+
+func (p *BackendPort) SetGroupVersionKind(schema.GroupVersionKind) {}
+
+func (p *BackendPort) GetLocator() string {
+	return namespacedSectionName(p.Backend.GetLocator(), p.Name)
+}
+
+func (p *BackendPort) GetNamespace() string {
+	return p.Backend.GetNamespace()
+}
+
+func (p *BackendPort) GetName() string {
+	return namespacedSectionName(p.Backend.GetName(), p.Name)
+}
+
+func (p *BackendPort) SetPolicies(policies []Policy) {
+	p.attachedPolicies = policies
+}
+
+func (p *BackendPort) Policies() []Policy {
+	return p.attachedPolicies
+}
@@ -0,0 +1,76 @@
+//go:build unit
+
+package machinery
+
+import (
+	"github.com/samber/lo"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// ServiceImport is a minimal multicluster ServiceImport, for registering backends of a kind other than core Services
+type ServiceImport struct {
+	metav1.ObjectMeta
+
+	Ports            []core.ServicePort
+	attachedPolicies []Policy
+}
+
+var _ Targetable = &ServiceImport{}
+
+var ServiceImportGroupKind = schema.GroupKind{Group: "multicluster.x-k8s.io", Kind: "ServiceImport"}
+
+func (s *ServiceImport) GroupVersionKind() schema.GroupVersionKind {
+	return ServiceImportGroupKind.WithVersion("v1alpha1")
+}
+
+func (s *ServiceImport) SetGroupVersionKind(schema.GroupVersionKind) {}
+
+func (s *ServiceImport) GetLocator() string {
+	return LocatorFromObject(s)
+}
+
+func (s *ServiceImport) SetPolicies(policies []Policy) {
+	s.attachedPolicies = policies
+}
+
+func (s *ServiceImport) Policies() []Policy {
+	return s.attachedPolicies
+}
+
+func BuildServiceImport(f ...func(*ServiceImport)) *ServiceImport {
+	s := &ServiceImport{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-service-import",
+			Namespace: "my-namespace",
+		},
+		Ports: []core.ServicePort{
+			{
+				Name: "http",
+				Port: 80,
+			},
+		},
+	}
+	for _, fn := range f {
+		fn(s)
+	}
+	return s
+}
+
+// ServiceImportBackendKind registers ServiceImports as a kind of backend, optionally expanded into their ports
+func ServiceImportBackendKind(expandPorts bool, serviceImports ...*ServiceImport) BackendKind {
+	backendKind := BackendKind{
+		GroupKind: ServiceImportGroupKind,
+		Backends:  lo.Map(serviceImports, func(s *ServiceImport, _ int) Targetable { return s }),
+	}
+	if expandPorts {
+		backendKind.Ports = func(backend Targetable) []*BackendPort {
+			return lo.Map(backend.(*ServiceImport).Ports, func(port core.ServicePort, _ int) *BackendPort {
+				return &BackendPort{Backend: backend, Kind: backendKind, Name: gwapiv1.SectionName(port.Name), Port: port.Port}
+			})
+		}
+	}
+	return backendKind
+}
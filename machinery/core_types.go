@@ -2,6 +2,7 @@ package machinery
 
 import (
 	core "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
@@ -10,6 +11,9 @@ var (
 	NamespaceGroupKind   = core.SchemeGroupVersion.WithKind("Namespace").GroupKind()
 	ServiceGroupKind     = core.SchemeGroupVersion.WithKind("Service").GroupKind()
 	ServicePortGroupKind = core.SchemeGroupVersion.WithKind("ServicePort").GroupKind()
+
+	EndpointSliceGroupKind = discovery.SchemeGroupVersion.WithKind("EndpointSlice").GroupKind()
+	EndpointZoneGroupKind  = discovery.SchemeGroupVersion.WithKind("EndpointZone").GroupKind()
 )
 
 // These are wrappers for Core API types so instances can be used as targetables in the topology.
@@ -90,3 +94,60 @@ func (p *ServicePort) SetPolicies(policies []Policy) {
 func (p *ServicePort) Policies() []Policy {
 	return p.attachedPolicies
 }
+
+type EndpointSlice struct {
+	*discovery.EndpointSlice
+
+	attachedPolicies []Policy
+}
+
+var _ Targetable = &EndpointSlice{}
+
+func (e *EndpointSlice) GetLocator() string {
+	return LocatorFromObject(e)
+}
+
+func (e *EndpointSlice) SetPolicies(policies []Policy) {
+	e.attachedPolicies = policies
+}
+
+func (e *EndpointSlice) Policies() []Policy {
+	return e.attachedPolicies
+}
+
+// EndpointZone is the set of endpoints of an EndpointSlice that live in the same zone.
+type EndpointZone struct {
+	Zone      string
+	Endpoints []discovery.Endpoint
+
+	EndpointSlice    *EndpointSlice
+	attachedPolicies []Policy
+}
+
+var _ Targetable = &EndpointZone{}
+
+func (z *EndpointZone) GroupVersionKind() schema.GroupVersionKind {
+	return discovery.SchemeGroupVersion.WithKind("EndpointZone")
+}
+
+func (z *EndpointZone) SetGroupVersionKind(schema.GroupVersionKind) {}
+
+func (z *EndpointZone) GetLocator() string {
+	return namespacedSectionName(LocatorFromObject(z.EndpointSlice), gwapiv1.SectionName(z.Zone))
+}
+
+func (z *EndpointZone) GetNamespace() string {
+	return z.EndpointSlice.GetNamespace()
+}
+
+func (z *EndpointZone) GetName() string {
+	return namespacedSectionName(z.EndpointSlice.Name, gwapiv1.SectionName(z.Zone))
+}
+
+func (z *EndpointZone) SetPolicies(policies []Policy) {
+	z.attachedPolicies = policies
+}
+
+func (z *EndpointZone) Policies() []Policy {
+	return z.attachedPolicies
+}
@@ -0,0 +1,217 @@
+package machinery
+
+import (
+	"fmt"
+
+	"github.com/samber/lo"
+	core "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// GatewayAPITopologyReport lists the Gateway API resources supplied to a topology that are orphaned or misconfigured,
+// i.e. the references and objects that the link functions of the topology could not resolve.
+type GatewayAPITopologyReport struct {
+	// UnresolvedParentRefs are the parentRefs of routes that resolve to no Gateway, ListenerSet or Listener, or to no
+	// Service when the mesh routes are enabled
+	UnresolvedParentRefs []UnresolvedParentRef
+	// UnresolvedBackendRefs are the backendRefs of routes that point at missing Services, service ports, or backends of
+	// the registered backend kinds. BackendRefs to kinds of backends not registered are disregarded.
+	UnresolvedBackendRefs []UnresolvedBackendRef
+	// GatewaysWithoutGatewayClass are the Gateways whose GatewayClass is missing
+	GatewaysWithoutGatewayClass []*Gateway
+	// ListenersWithoutRoutes are the listeners of the Gateways and ListenerSets to which no route attaches
+	ListenersWithoutRoutes []*Listener
+}
+
+// Empty tells whether the report lists no resources.
+func (r *GatewayAPITopologyReport) Empty() bool {
+	return len(r.UnresolvedParentRefs) == 0 && len(r.UnresolvedBackendRefs) == 0 && len(r.GatewaysWithoutGatewayClass) == 0 && len(r.ListenersWithoutRoutes) == 0
+}
+
+// UnresolvedParentRef is a parentRef of a route that resolves to no parent in the topology.
+type UnresolvedParentRef struct {
+	Route     Targetable
+	ParentRef gwapiv1.ParentReference
+	Reason    gwapiv1.RouteConditionReason
+	Message   string
+}
+
+// UnresolvedBackendRef is a backendRef of a route that resolves to no backend in the topology.
+type UnresolvedBackendRef struct {
+	Route      Targetable
+	BackendRef gwapiv1.BackendRef
+	Reason     gwapiv1.RouteConditionReason
+	Message    string
+}
+
+// knownParentKeys returns the index keys of the Gateways and ListenerSets, and of their listeners, that the parentRefs
+// of the routes can resolve to. ListenerSets whose parent Gateway is missing are not included, as they are left out of
+// the topology along with their listeners.
+func knownParentKeys(gateways []*Gateway, listenerSets []*ListenerSet) map[string]struct{} {
+	keys := make(map[string]struct{})
+	for _, gateway := range gateways {
+		keys[gatewayKey(gateway)] = struct{}{}
+		for _, listener := range ListenersFromGatewayFunc(gateway, 0) {
+			for _, key := range listenerKeys(listener) {
+				keys[key] = struct{}{}
+			}
+		}
+	}
+	for _, listenerSet := range listenerSets {
+		if _, ok := keys[gatewayKeyFromListenerSet(listenerSet)]; !ok {
+			continue
+		}
+		keys[listenerSetKey(listenerSet)] = struct{}{}
+		for _, listener := range ListenersFromListenerSetFunc(listenerSet, 0) {
+			for _, key := range listenerKeys(listener) {
+				keys[key] = struct{}{}
+			}
+		}
+	}
+	return keys
+}
+
+// gatewayAPITopologyReport analyses the resources in the options of a Gateway API topology.
+// The parentRefs of the routes are resolved against the given parent keys, so references to Gateways left out of the
+// topology (e.g. for belonging to other controllers) are not reported. Listeners are reported as without routes when no
+// route attaches to them via the given link functions.
+func gatewayAPITopologyReport(o *GatewayAPITopologyOptions, parentKeys map[string]struct{}, listeners []*Listener, routeAttachmentLinks []LinkFunc) *GatewayAPITopologyReport {
+	report := &GatewayAPITopologyReport{}
+
+	routes := lo.Flatten([][]Targetable{
+		lo.Map(o.HTTPRoutes, func(route *HTTPRoute, _ int) Targetable { return route }),
+		lo.Map(o.GRPCRoutes, func(route *GRPCRoute, _ int) Targetable { return route }),
+		lo.Map(o.TCPRoutes, func(route *TCPRoute, _ int) Targetable { return route }),
+		lo.Map(o.TLSRoutes, func(route *TLSRoute, _ int) Targetable { return route }),
+		lo.Map(o.UDPRoutes, func(route *UDPRoute, _ int) Targetable { return route }),
+	})
+
+	services := lo.SliceToMap(o.Services, func(service *Service) (string, *Service) {
+		return serviceKey(service), service
+	})
+
+	// parentRefs
+	for _, route := range routes {
+		for _, parentRef := range parentRefsFromRoute(route) {
+			if message, ok := unresolvedParentRefMessage(parentRef, route.GetNamespace(), parentKeys, services, o.MeshRoutes); !ok {
+				report.UnresolvedParentRefs = append(report.UnresolvedParentRefs, UnresolvedParentRef{
+					Route:     route,
+					ParentRef: parentRef,
+					Reason:    gwapiv1.RouteReasonNoMatchingParent,
+					Message:   message,
+				})
+			}
+		}
+	}
+
+	// backendRefs
+	type backend struct {
+		kind    BackendKind
+		backend Targetable
+	}
+	backends := make(map[string]backend)
+	for _, backendKind := range o.BackendKinds {
+		for _, b := range backendKind.Backends {
+			backends[backendKindKey(backendKind, b)] = backend{kind: backendKind, backend: b}
+		}
+	}
+	for _, route := range routes {
+		for _, backendRef := range backendRefsFromRoute(route) {
+			key := backendRefKey(backendRef, route.GetNamespace())
+			kind := string(ptr.Deref(backendRef.Kind, "Service"))
+			name := namespacedName(string(ptr.Deref(backendRef.Namespace, gwapiv1.Namespace(route.GetNamespace()))), string(backendRef.Name))
+			var message string
+			if ptr.Deref(backendRef.Group, "") == gwapiv1.Group(ServiceGroupKind.Group) && kind == ServiceGroupKind.Kind {
+				service, found := services[key]
+				switch {
+				case !found:
+					message = fmt.Sprintf("%s %s not found", kind, name)
+				case backendRef.Port != nil && !lo.ContainsBy(service.Spec.Ports, func(port core.ServicePort) bool { return port.Port == int32(*backendRef.Port) }):
+					message = fmt.Sprintf("port %d not found in %s %s", *backendRef.Port, kind, name)
+				}
+			} else if b, found := backends[key]; found {
+				if backendRef.Port != nil && b.kind.Ports != nil && !lo.ContainsBy(b.kind.Ports(b.backend), func(port *BackendPort) bool { return port.Port == int32(*backendRef.Port) }) {
+					message = fmt.Sprintf("port %d not found in %s %s", *backendRef.Port, kind, name)
+				}
+			} else if lo.ContainsBy(o.BackendKinds, func(backendKind BackendKind) bool {
+				return backendKind.GroupKind.Group == string(ptr.Deref(backendRef.Group, "")) && backendKind.GroupKind.Kind == kind
+			}) {
+				message = fmt.Sprintf("%s %s not found", kind, name)
+			}
+			if message != "" {
+				report.UnresolvedBackendRefs = append(report.UnresolvedBackendRefs, UnresolvedBackendRef{
+					Route:      route,
+					BackendRef: backendRef,
+					Reason:     gwapiv1.RouteReasonBackendNotFound,
+					Message:    message,
+				})
+			}
+		}
+	}
+
+	// gateways
+	gatewayClassNames := lo.SliceToMap(o.GatewayClasses, func(gatewayClass *GatewayClass) (string, struct{}) {
+		return gatewayClass.Name, struct{}{}
+	})
+	report.GatewaysWithoutGatewayClass = lo.Filter(o.Gateways, func(gateway *Gateway, _ int) bool {
+		_, found := gatewayClassNames[string(gateway.Spec.GatewayClassName)]
+		return !found
+	})
+
+	// listeners
+	attachedListeners := make(map[string]struct{})
+	for _, link := range routeAttachmentLinks {
+		for _, route := range routes {
+			if route.GroupVersionKind().GroupKind() != link.To {
+				continue
+			}
+			for _, listener := range link.Func(route) {
+				attachedListeners[listener.GetLocator()] = struct{}{}
+			}
+		}
+	}
+	report.ListenersWithoutRoutes = lo.Filter(listeners, func(listener *Listener, _ int) bool {
+		_, attached := attachedListeners[listener.GetLocator()]
+		return !attached
+	})
+
+	return report
+}
+
+// unresolvedParentRefMessage tells whether a parentRef of a route resolves to a known parent. If not, it returns a
+// message explaining why. ParentRefs to kinds of parents other than Gateways, ListenerSets and Services (when the mesh
+// routes are enabled) are disregarded.
+func unresolvedParentRefMessage(parentRef gwapiv1.ParentReference, routeNamespace string, parentKeys map[string]struct{}, services map[string]*Service, meshRoutes bool) (string, bool) {
+	kind := string(ptr.Deref(parentRef.Kind, "Gateway"))
+	name := namespacedName(string(ptr.Deref(parentRef.Namespace, gwapiv1.Namespace(routeNamespace))), string(parentRef.Name))
+
+	if key, ok := serviceKeyFromParentRef(parentRef, routeNamespace); ok {
+		if !meshRoutes {
+			return "", true
+		}
+		service, found := services[key]
+		switch {
+		case !found:
+			return fmt.Sprintf("%s %s not found", kind, name), false
+		case parentRef.Port != nil && !lo.ContainsBy(service.Spec.Ports, func(port core.ServicePort) bool { return port.Port == int32(*parentRef.Port) }):
+			return fmt.Sprintf("port %d not found in %s %s", *parentRef.Port, kind, name), false
+		case parentRef.SectionName != nil && !lo.ContainsBy(service.Spec.Ports, func(port core.ServicePort) bool { return port.Name == string(*parentRef.SectionName) }):
+			return fmt.Sprintf("port %s not found in %s %s", *parentRef.SectionName, kind, name), false
+		}
+		return "", true
+	}
+
+	key, ok := parentKeyFromParentRef(parentRef, routeNamespace)
+	if !ok {
+		return "", true
+	}
+	if _, found := parentKeys[key]; !found {
+		return fmt.Sprintf("%s %s not found", kind, name), false
+	}
+	listenerKey, _ := listenerKeyFromParentRefFunc(routeNamespace)(parentRef, 0)
+	if _, found := parentKeys[listenerKey]; !found {
+		return fmt.Sprintf("no listener of %s %s matches the sectionName and port of the parentRef", kind, name), false
+	}
+	return "", true
+}
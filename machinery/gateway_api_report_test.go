@@ -0,0 +1,213 @@
+//go:build unit
+
+package machinery
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/samber/lo"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestGatewayAPITopologyReport(t *testing.T) {
+	gateways := []*gwapiv1.Gateway{
+		BuildGateway(func(g *gwapiv1.Gateway) {
+			g.Spec.Listeners = append(g.Spec.Listeners, gwapiv1.Listener{Name: "idle-listener", Port: 9000, Protocol: "HTTP"})
+		}),
+		BuildGateway(func(g *gwapiv1.Gateway) {
+			g.Name = "classless-gateway"
+			g.Spec.GatewayClassName = "missing-gateway-class"
+			g.Spec.Listeners[0].Name = "classless-listener"
+		}),
+	}
+	httpRoutes := []*gwapiv1.HTTPRoute{
+		BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Spec.ParentRefs[0].SectionName = ptr.To(gwapiv1.SectionName("my-listener"))
+			r.Spec.ParentRefs = append(r.Spec.ParentRefs, gwapiv1.ParentReference{Name: "classless-gateway"})
+		}),
+		BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Name = "orphan-route"
+			r.Spec.ParentRefs = []gwapiv1.ParentReference{
+				{Name: "missing-gateway"},
+				{Name: "my-gateway", SectionName: ptr.To(gwapiv1.SectionName("missing-listener"))},
+				{Name: "my-gateway", Port: ptr.To(gwapiv1.PortNumber(8443))},
+				{Group: ptr.To(gwapiv1.Group("example.com")), Kind: ptr.To(gwapiv1.Kind("Unknown")), Name: "unknown-parent"},
+			}
+			r.Spec.Rules[0].BackendRefs = []gwapiv1.HTTPBackendRef{
+				BuildHTTPBackendRef(func(b *gwapiv1.BackendObjectReference) { b.Name = "missing-service" }),
+				BuildHTTPBackendRef(func(b *gwapiv1.BackendObjectReference) { b.Port = ptr.To(gwapiv1.PortNumber(8080)) }),
+				BuildHTTPBackendRef(func(b *gwapiv1.BackendObjectReference) { b.Port = ptr.To(gwapiv1.PortNumber(80)) }),
+				BuildHTTPBackendRef(func(b *gwapiv1.BackendObjectReference) {
+					b.Group = ptr.To(gwapiv1.Group(ServiceImportGroupKind.Group))
+					b.Kind = ptr.To(gwapiv1.Kind(ServiceImportGroupKind.Kind))
+					b.Name = "missing-service-import"
+				}),
+				BuildHTTPBackendRef(func(b *gwapiv1.BackendObjectReference) {
+					b.Group = ptr.To(gwapiv1.Group(ServiceImportGroupKind.Group))
+					b.Kind = ptr.To(gwapiv1.Kind(ServiceImportGroupKind.Kind))
+					b.Name = "my-service-import"
+					b.Port = ptr.To(gwapiv1.PortNumber(8080))
+				}),
+				BuildHTTPBackendRef(func(b *gwapiv1.BackendObjectReference) {
+					b.Group = ptr.To(gwapiv1.Group("example.com"))
+					b.Kind = ptr.To(gwapiv1.Kind("Unknown"))
+					b.Name = "unknown-backend"
+				}),
+			}
+		}),
+	}
+
+	options := []GatewayAPITopologyOptionsFunc{
+		WithGatewayClasses(BuildGatewayClass()),
+		WithGateways(gateways...),
+		WithHTTPRoutes(httpRoutes...),
+		WithServices(BuildService()),
+		WithBackendKinds(ServiceImportBackendKind(true, BuildServiceImport())),
+	}
+
+	for _, expandListeners := range []bool{false, true} {
+		t.Run(fmt.Sprintf("expand listeners %t", expandListeners), func(t *testing.T) {
+			opts := slices.Clone(options)
+			if expandListeners {
+				opts = append(opts, ExpandGatewayListeners())
+			}
+			_, report, err := NewGatewayAPITopologyWithReport(opts...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if report == nil || report.Empty() {
+				t.Fatalf("expected a report, got %v", report)
+			}
+
+			parentRefs := lo.Map(report.UnresolvedParentRefs, func(ref UnresolvedParentRef, _ int) string {
+				return fmt.Sprintf("%s: %s (%s)", ref.Route.GetName(), ref.Message, ref.Reason)
+			})
+			expectedParentRefs := []string{
+				"orphan-route: Gateway my-namespace/missing-gateway not found (NoMatchingParent)",
+				"orphan-route: no listener of Gateway my-namespace/my-gateway matches the sectionName and port of the parentRef (NoMatchingParent)",
+				"orphan-route: no listener of Gateway my-namespace/my-gateway matches the sectionName and port of the parentRef (NoMatchingParent)",
+			}
+			if !slices.Equal(parentRefs, expectedParentRefs) {
+				t.Errorf("expected unresolved parentRefs %v, got %v", expectedParentRefs, parentRefs)
+			}
+
+			backendRefs := lo.Map(report.UnresolvedBackendRefs, func(ref UnresolvedBackendRef, _ int) string {
+				return fmt.Sprintf("%s: %s (%s)", ref.Route.GetName(), ref.Message, ref.Reason)
+			})
+			expectedBackendRefs := []string{
+				"orphan-route: Service my-namespace/missing-service not found (BackendNotFound)",
+				"orphan-route: port 8080 not found in Service my-namespace/my-service (BackendNotFound)",
+				"orphan-route: ServiceImport my-namespace/missing-service-import not found (BackendNotFound)",
+				"orphan-route: port 8080 not found in ServiceImport my-namespace/my-service-import (BackendNotFound)",
+			}
+			if !slices.Equal(backendRefs, expectedBackendRefs) {
+				t.Errorf("expected unresolved backendRefs %v, got %v", expectedBackendRefs, backendRefs)
+			}
+
+			classless := lo.Map(report.GatewaysWithoutGatewayClass, func(gateway *Gateway, _ int) string { return gateway.GetName() })
+			if !slices.Equal(classless, []string{"classless-gateway"}) {
+				t.Errorf("expected gateways without gateway class [classless-gateway], got %v", classless)
+			}
+
+			idle := lo.Map(report.ListenersWithoutRoutes, func(listener *Listener, _ int) string { return listener.GetName() })
+			if !slices.Equal(idle, []string{"my-gateway#idle-listener"}) {
+				t.Errorf("expected listeners without routes [my-gateway#idle-listener], got %v", idle)
+			}
+		})
+	}
+
+	t.Run("listeners that do not allow the routes", func(t *testing.T) {
+		_, report, err := NewGatewayAPITopologyWithReport(
+			WithGatewayClasses(BuildGatewayClass()),
+			WithGateways(BuildGateway(func(g *gwapiv1.Gateway) {
+				g.Spec.Listeners[0].AllowedRoutes = &gwapiv1.AllowedRoutes{Kinds: []gwapiv1.RouteGroupKind{{Kind: "GRPCRoute"}}}
+			})),
+			WithHTTPRoutes(BuildHTTPRoute()),
+			WithServices(BuildService()),
+			EnforceAllowedRoutes(),
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		idle := lo.Map(report.ListenersWithoutRoutes, func(listener *Listener, _ int) string { return listener.GetName() })
+		if !slices.Equal(idle, []string{"my-gateway#my-listener"}) {
+			t.Errorf("expected listeners without routes [my-gateway#my-listener], got %v", idle)
+		}
+	})
+
+	t.Run("listener sets", func(t *testing.T) {
+		buildHTTPRoute := func(name string, parentName gwapiv1.ObjectName, sectionName gwapiv1.SectionName) *gwapiv1.HTTPRoute {
+			return BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+				r.Name = name
+				r.Spec.ParentRefs[0].Kind = ptr.To(gwapiv1.Kind("ListenerSet"))
+				r.Spec.ParentRefs[0].Name = parentName
+				r.Spec.ParentRefs[0].SectionName = ptr.To(sectionName)
+			})
+		}
+		for _, expandListeners := range []bool{false, true} {
+			t.Run(fmt.Sprintf("expand listeners %t", expandListeners), func(t *testing.T) {
+				opts := []GatewayAPITopologyOptionsFunc{
+					WithGatewayClasses(BuildGatewayClass()),
+					WithGateways(BuildGateway()),
+					WithListenerSets(BuildListenerSet(), BuildListenerSet(func(l *gwapiv1.ListenerSet) {
+						l.Name = "orphan-listener-set"
+						l.Spec.ParentRef.Name = "missing-gateway"
+					})),
+					WithHTTPRoutes(
+						BuildHTTPRoute(),
+						buildHTTPRoute("listener-set-route", "my-listener-set", "my-listener-set-listener"),
+						buildHTTPRoute("orphan-listener-set-route", "orphan-listener-set", "my-listener-set-listener"),
+					),
+					WithServices(BuildService()),
+				}
+				if expandListeners {
+					opts = append(opts, ExpandGatewayListeners())
+				}
+				_, report, err := NewGatewayAPITopologyWithReport(opts...)
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+
+				parentRefs := lo.Map(report.UnresolvedParentRefs, func(ref UnresolvedParentRef, _ int) string {
+					return fmt.Sprintf("%s: %s (%s)", ref.Route.GetName(), ref.Message, ref.Reason)
+				})
+				expectedParentRefs := []string{
+					"orphan-listener-set-route: ListenerSet my-namespace/orphan-listener-set not found (NoMatchingParent)",
+				}
+				if !slices.Equal(parentRefs, expectedParentRefs) {
+					t.Errorf("expected unresolved parentRefs %v, got %v", expectedParentRefs, parentRefs)
+				}
+				if len(report.ListenersWithoutRoutes) > 0 {
+					t.Errorf("expected no listeners without routes, got %v", lo.Map(report.ListenersWithoutRoutes, func(listener *Listener, _ int) string { return listener.GetName() }))
+				}
+			})
+		}
+	})
+
+	t.Run("parentRefs to gateways out of scope", func(t *testing.T) {
+		_, report, err := NewGatewayAPITopologyWithReport(
+			WithGatewayClasses(BuildGatewayClass(), BuildGatewayClass(func(gc *gwapiv1.GatewayClass) {
+				gc.Name = "other-gateway-class"
+				gc.Spec.ControllerName = "other-controller"
+			})),
+			WithGateways(BuildGateway(), BuildGateway(func(g *gwapiv1.Gateway) {
+				g.Name = "other-gateway"
+				g.Spec.GatewayClassName = "other-gateway-class"
+			})),
+			WithHTTPRoutes(BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+				r.Spec.ParentRefs = append(r.Spec.ParentRefs, gwapiv1.ParentReference{Name: "other-gateway"})
+			})),
+			WithServices(BuildService()),
+			WithGatewayControllerNames("my-gateway-controller"),
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if !report.Empty() {
+			t.Errorf("expected an empty report, got %+v", report)
+		}
+	})
+}
@@ -4,11 +4,61 @@ package machinery
 
 import (
 	core "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
+func BuildNamespace(f ...func(*core.Namespace)) *core.Namespace {
+	ns := &core.Namespace{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: core.SchemeGroupVersion.String(),
+			Kind:       "Namespace",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-namespace",
+		},
+	}
+	for _, fn := range f {
+		fn(ns)
+	}
+	return ns
+}
+
+func BuildReferenceGrant(f ...func(*gwapiv1beta1.ReferenceGrant)) *gwapiv1beta1.ReferenceGrant {
+	rg := &gwapiv1beta1.ReferenceGrant{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gwapiv1beta1.GroupVersion.String(),
+			Kind:       "ReferenceGrant",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-reference-grant",
+			Namespace: "my-namespace",
+		},
+		Spec: gwapiv1beta1.ReferenceGrantSpec{
+			From: []gwapiv1beta1.ReferenceGrantFrom{
+				{
+					Group:     gwapiv1.GroupName,
+					Kind:      "HTTPRoute",
+					Namespace: "other-namespace",
+				},
+			},
+			To: []gwapiv1beta1.ReferenceGrantTo{
+				{
+					Group: "",
+					Kind:  "Service",
+				},
+			},
+		},
+	}
+	for _, fn := range f {
+		fn(rg)
+	}
+	return rg
+}
+
 func BuildGatewayClass(f ...func(*gwapiv1.GatewayClass)) *gwapiv1.GatewayClass {
 	gc := &gwapiv1.GatewayClass{
 		TypeMeta: metav1.TypeMeta{
@@ -55,6 +105,35 @@ func BuildGateway(f ...func(*gwapiv1.Gateway)) *gwapiv1.Gateway {
 	return g
 }
 
+func BuildListenerSet(f ...func(*gwapiv1.ListenerSet)) *gwapiv1.ListenerSet {
+	l := &gwapiv1.ListenerSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: gwapiv1.GroupVersion.String(),
+			Kind:       "ListenerSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-listener-set",
+			Namespace: "my-namespace",
+		},
+		Spec: gwapiv1.ListenerSetSpec{
+			ParentRef: gwapiv1.ParentGatewayReference{
+				Name: "my-gateway",
+			},
+			Listeners: []gwapiv1.ListenerEntry{
+				{
+					Name:     "my-listener-set-listener",
+					Port:     8080,
+					Protocol: "HTTP",
+				},
+			},
+		},
+	}
+	for _, fn := range f {
+		fn(l)
+	}
+	return l
+}
+
 func BuildHTTPRoute(f ...func(*gwapiv1.HTTPRoute)) *gwapiv1.HTTPRoute {
 	r := &gwapiv1.HTTPRoute{
 		TypeMeta: metav1.TypeMeta{
@@ -120,6 +199,43 @@ func BuildService(f ...func(*core.Service)) *core.Service {
 	return s
 }
 
+func BuildEndpointSlice(f ...func(*discovery.EndpointSlice)) *discovery.EndpointSlice {
+	e := &discovery.EndpointSlice{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: discovery.SchemeGroupVersion.String(),
+			Kind:       "EndpointSlice",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-service-abc12",
+			Namespace: "my-namespace",
+			Labels: map[string]string{
+				discovery.LabelServiceName: "my-service",
+			},
+		},
+		AddressType: discovery.AddressTypeIPv4,
+		Endpoints: []discovery.Endpoint{
+			{
+				Addresses: []string{"10.0.0.1"},
+				Zone:      ptr.To("zone-a"),
+			},
+			{
+				Addresses: []string{"10.0.0.2"},
+				Zone:      ptr.To("zone-b"),
+			},
+		},
+		Ports: []discovery.EndpointPort{
+			{
+				Name: ptr.To("http"),
+				Port: ptr.To(int32(8080)),
+			},
+		},
+	}
+	for _, fn := range f {
+		fn(e)
+	}
+	return e
+}
+
 func BuildGRPCRoute(f ...func(*gwapiv1.GRPCRoute)) *gwapiv1.GRPCRoute {
 	r := &gwapiv1.GRPCRoute{
 		TypeMeta: metav1.TypeMeta{
@@ -2,39 +2,78 @@ package machinery
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/samber/lo"
 	core "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8slabels "k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/ptr"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 type GatewayAPITopologyOptions struct {
-	GatewayClasses []*GatewayClass
-	Gateways       []*Gateway
-	HTTPRoutes     []*HTTPRoute
-	GRPCRoutes     []*GRPCRoute
-	TCPRoutes      []*TCPRoute
-	TLSRoutes      []*TLSRoute
-	UDPRoutes      []*UDPRoute
-	Services       []*Service
-	Policies       []Policy
-	Objects        []Object
-	Links          []LinkFunc
+	Namespaces      []*Namespace
+	GatewayClasses  []*GatewayClass
+	Gateways        []*Gateway
+	ListenerSets    []*ListenerSet
+	HTTPRoutes      []*HTTPRoute
+	GRPCRoutes      []*GRPCRoute
+	TCPRoutes       []*TCPRoute
+	TLSRoutes       []*TLSRoute
+	UDPRoutes       []*UDPRoute
+	Services        []*Service
+	EndpointSlices  []*EndpointSlice
+	BackendKinds    []BackendKind
+	ReferenceGrants []*ReferenceGrant
+	Policies        []Policy
+	Objects         []Object
+	Links           []LinkFunc
+
+	SectionExpanders []SectionExpander
 
 	ExpandGatewayListeners bool
+	ExpandHostnames        bool
 	ExpandHTTPRouteRules   bool
 	ExpandGRPCRouteRules   bool
+	ExpandHTTPRouteMatches bool
+	ExpandGRPCRouteMatches bool
 	ExpandTCPRouteRules    bool
 	ExpandTLSRouteRules    bool
 	ExpandUDPRouteRules    bool
 	ExpandServicePorts     bool
+	ExpandEndpointZones    bool
+
+	MeshRoutes             bool
+	RequireReferenceGrants bool
+	EnforceAllowedRoutes   bool
+	EnforceHostnames       bool
+
+	TrustRouteParentStatus     bool
+	RouteParentControllerNames []gwapiv1.GatewayController
+	SkipUnprogrammedGateways   bool
+	GatewayControllerNames     []gwapiv1.GatewayController
+
+	Parallelism int
 
 	allowTopologyLoops bool
 }
 
 type GatewayAPITopologyOptionsFunc func(*GatewayAPITopologyOptions)
 
+// WithNamespaces adds namespaces to the options to initialize a new Gateway API topology.
+func WithNamespaces(namespaces ...*core.Namespace) GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.Namespaces = append(o.Namespaces, lo.Map(namespaces, func(namespace *core.Namespace, _ int) *Namespace {
+			return &Namespace{Namespace: namespace}
+		})...)
+	}
+}
+
 // WithGatewayClasses adds gateway classes to the options to initialize a new Gateway API topology.
 func WithGatewayClasses(gatewayClasses ...*gwapiv1.GatewayClass) GatewayAPITopologyOptionsFunc {
 	return func(o *GatewayAPITopologyOptions) {
@@ -53,6 +92,16 @@ func WithGateways(gateways ...*gwapiv1.Gateway) GatewayAPITopologyOptionsFunc {
 	}
 }
 
+// WithListenerSets adds listener sets to the options to initialize a new Gateway API topology.
+// ListenerSets whose parent Gateway is not in the topology are left out.
+func WithListenerSets(listenerSets ...*gwapiv1.ListenerSet) GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.ListenerSets = append(o.ListenerSets, lo.Map(listenerSets, func(listenerSet *gwapiv1.ListenerSet, _ int) *ListenerSet {
+			return &ListenerSet{ListenerSet: listenerSet}
+		})...)
+	}
+}
+
 // WithHTTPRoutes adds HTTP routes to the options to initialize a new Gateway API topology.
 func WithHTTPRoutes(httpRoutes ...*gwapiv1.HTTPRoute) GatewayAPITopologyOptionsFunc {
 	return func(o *GatewayAPITopologyOptions) {
@@ -107,6 +156,36 @@ func WithServices(services ...*core.Service) GatewayAPITopologyOptionsFunc {
 	}
 }
 
+// WithEndpointSlices adds EndpointSlices to the options to initialize a new Gateway API topology.
+// The EndpointSlices are linked from the Services named in their `kubernetes.io/service-name` label, or from the
+// service ports when the ports are expanded.
+func WithEndpointSlices(endpointSlices ...*discovery.EndpointSlice) GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.EndpointSlices = append(o.EndpointSlices, lo.Map(endpointSlices, func(endpointSlice *discovery.EndpointSlice, _ int) *EndpointSlice {
+			return &EndpointSlice{EndpointSlice: endpointSlice}
+		})...)
+	}
+}
+
+// WithBackendKinds registers kinds of backends other than core Services in the options to initialize a new Gateway API
+// topology. The backends of each kind are linked from the routes, or from the route rules when the rules are expanded,
+// that refer to them in their backendRefs.
+func WithBackendKinds(backendKinds ...BackendKind) GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.BackendKinds = append(o.BackendKinds, backendKinds...)
+	}
+}
+
+// WithReferenceGrants adds reference grants to the options to initialize a new Gateway API topology.
+// ReferenceGrants are added to the topology as objects, and are only enforced when RequireReferenceGrants is set.
+func WithReferenceGrants(referenceGrants ...*gwapiv1beta1.ReferenceGrant) GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.ReferenceGrants = append(o.ReferenceGrants, lo.Map(referenceGrants, func(referenceGrant *gwapiv1beta1.ReferenceGrant, _ int) *ReferenceGrant {
+			return &ReferenceGrant{ReferenceGrant: referenceGrant}
+		})...)
+	}
+}
+
 // WithGatewayAPITopologyPolicies adds policies to the options to initialize a new Gateway API topology.
 func WithGatewayAPITopologyPolicies(policies ...Policy) GatewayAPITopologyOptionsFunc {
 	return func(o *GatewayAPITopologyOptions) {
@@ -130,13 +209,39 @@ func WithGatewayAPITopologyLinks(links ...LinkFunc) GatewayAPITopologyOptionsFun
 	}
 }
 
+// WithGatewayAPITopologySectionExpanders adds section expanders to the options to initialize a new Gateway API
+// topology, to expand the sections of targetables of other kinds than the ones defined by Gateway API (e.g. backends
+// of custom kinds), or of the ones defined by Gateway API for which there is no built-in expansion.
+func WithGatewayAPITopologySectionExpanders(expanders ...SectionExpander) GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.SectionExpanders = append(o.SectionExpanders, expanders...)
+	}
+}
+
 // ExpandGatewayListeners adds targetable gateway listeners to the options to initialize a new Gateway API topology.
+// The listeners of the ListenerSets are expanded as well.
 func ExpandGatewayListeners() GatewayAPITopologyOptionsFunc {
 	return func(o *GatewayAPITopologyOptions) {
 		o.ExpandGatewayListeners = true
 	}
 }
 
+// ExpandHostnames adds targetable hostnames to the options to initialize a new Gateway API topology.
+// The Gateway listeners are expanded as well. A hostname is added for the hostname of each listener, and for each
+// hostname shared by a listener and a route attached to it, as returned by EffectiveHostnames. Routes are then linked
+// from the listeners through their hostnames (Listener -> Hostname -> Route), or directly from the listeners when they
+// share no specific hostname, e.g. when neither the listener nor the route specify any.
+// Hostnames are sections of the listeners that serve them, so a policy that targets a hostname only applies through the
+// listener that serves it. Policies target a hostname by the kind and name of the Gateway or ListenerSet that defines the
+// listener, and the section name `<listener>.<hostname>`, with the `*` of a wildcard hostname spelled out as `wildcard`
+// (see Hostname.SectionName).
+func ExpandHostnames() GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.ExpandGatewayListeners = true
+		o.ExpandHostnames = true
+	}
+}
+
 // ExpandHTTPRouteRules adds targetable HTTP route rules to the options to initialize a new Gateway API topology.
 func ExpandHTTPRouteRules() GatewayAPITopologyOptionsFunc {
 	return func(o *GatewayAPITopologyOptions) {
@@ -151,6 +256,26 @@ func ExpandGRPCRouteRules() GatewayAPITopologyOptionsFunc {
 	}
 }
 
+// ExpandHTTPRouteMatches adds targetable HTTP route matches to the options to initialize a new Gateway API topology.
+// The HTTP route rules are expanded as well. The section name of each match is the name of the rule followed by the
+// position of the match in the rule, e.g. `rule-1.match-2`, so policies can target a single match of a rule.
+func ExpandHTTPRouteMatches() GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.ExpandHTTPRouteRules = true
+		o.ExpandHTTPRouteMatches = true
+	}
+}
+
+// ExpandGRPCRouteMatches adds targetable GRPC route matches to the options to initialize a new Gateway API topology.
+// The GRPC route rules are expanded as well. The section name of each match is the name of the rule followed by the
+// position of the match in the rule, e.g. `rule-1.match-2`, so policies can target a single match of a rule.
+func ExpandGRPCRouteMatches() GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.ExpandGRPCRouteRules = true
+		o.ExpandGRPCRouteMatches = true
+	}
+}
+
 // ExpandTCPRouteRules adds targetable TCP route rules to the options to initialize a new Gateway API topology.
 func ExpandTCPRouteRules() GatewayAPITopologyOptionsFunc {
 	return func(o *GatewayAPITopologyOptions) {
@@ -179,6 +304,88 @@ func ExpandServicePorts() GatewayAPITopologyOptionsFunc {
 	}
 }
 
+// ExpandEndpointZones adds targetable endpoint zones to the options to initialize a new Gateway API topology.
+// The endpoints of each EndpointSlice are grouped by the zone they live in. Endpoints with no zone are left out.
+func ExpandEndpointZones() GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.ExpandEndpointZones = true
+	}
+}
+
+// EnableMeshRoutes links HTTPRoutes and GRPCRoutes from the Services they refer to in their `parentRefs` field, as in
+// the Gateway API mesh (GAMMA) model. When the service ports are expanded, routes whose parent references specify a
+// port number or a port name are linked from the service port instead.
+// Routes are not linked to the Services they are attached to as parents, even if those are also among their backends,
+// which would otherwise form loops in the topology.
+func EnableMeshRoutes() GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.MeshRoutes = true
+	}
+}
+
+// RequireReferenceGrants restricts the links from routes and route rules to Services and service ports in other
+// namespaces to the ones permitted by a ReferenceGrant in the namespace of the Service.
+// The backendRefs not permitted are recorded in the RefusedBackendRefs field of the routes, e.g. for reporting the
+// `RefNotPermitted` reason in the status of the routes.
+func RequireReferenceGrants() GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.RequireReferenceGrants = true
+	}
+}
+
+// EnforceAllowedRoutes restricts the links from Gateways and Listeners to routes to the ones allowed by the
+// `allowedRoutes` field and the protocol of the Listeners. Namespaces must be supplied with WithNamespaces for the
+// Listeners that select the namespaces of the routes by labels.
+func EnforceAllowedRoutes() GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.EnforceAllowedRoutes = true
+	}
+}
+
+// EnforceHostnameIntersection restricts the links from Gateways and Listeners to routes to the ones whose hostnames
+// intersect. The resulting hostnames of each link can be obtained with EffectiveHostnames.
+func EnforceHostnameIntersection() GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.EnforceHostnames = true
+	}
+}
+
+// TrustRouteParentStatus links routes to Gateways and Listeners only when the status of the route reports the parent
+// reference as accepted (`Accepted=True`) by a gateway controller, instead of relying on the spec of the route alone.
+// The accepted status must be reported by one of the given controller names, or by the controller of the GatewayClass
+// of the Gateway if no controller names are given.
+func TrustRouteParentStatus(controllerNames ...gwapiv1.GatewayController) GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.TrustRouteParentStatus = true
+		o.RouteParentControllerNames = append(o.RouteParentControllerNames, controllerNames...)
+	}
+}
+
+// SkipUnprogrammedGateways leaves out of the topology the Gateways whose `Programmed` condition is false, along with
+// their ListenerSets.
+func SkipUnprogrammedGateways() GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.SkipUnprogrammedGateways = true
+	}
+}
+
+// WithGatewayControllerNames scopes the topology to the GatewayClasses whose `controllerName` is one of the given
+// names. GatewayClasses of other controllers, their Gateways, the routes that only attach to those Gateways, and the
+// Services only referred by those routes are left out of the topology.
+func WithGatewayControllerNames(controllerNames ...gwapiv1.GatewayController) GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.GatewayControllerNames = append(o.GatewayControllerNames, controllerNames...)
+	}
+}
+
+// WithGatewayAPITopologyParallelism sets the maximum number of expansion steps and link functions evaluated
+// concurrently when building a new Gateway API topology. The output is the same as the one of a topology built serially.
+func WithGatewayAPITopologyParallelism(n int) GatewayAPITopologyOptionsFunc {
+	return func(o *GatewayAPITopologyOptions) {
+		o.Parallelism = n
+	}
+}
+
 // AllowTopologyLoops adds AllowLoops to the options to initialize a new Gateway API topology.
 func AllowTopologyLoops() GatewayAPITopologyOptionsFunc {
 	return func(o *GatewayAPITopologyOptions) {
@@ -189,6 +396,8 @@ func AllowTopologyLoops() GatewayAPITopologyOptionsFunc {
 // NewGatewayAPITopology returns a topology of Gateway API objects and attached policies.
 //
 // The links between the targetables are established based on the relationships defined by Gateway API.
+// Namespaces, when supplied, are linked to the Gateways and routes that live in them, so policies attached to a
+// Namespace apply to the paths that start at the Namespace.
 //
 // Principal objects like Gateways, HTTPRoutes and Services can be expanded to automatically include their targetable
 // sections (listeners, route rules, service ports) as independent objects in the topology, by supplying the
@@ -196,53 +405,272 @@ func AllowTopologyLoops() GatewayAPITopologyOptionsFunc {
 // The links will then be established accordingly. E.g.:
 //   - Without expanding Gateway listeners (default): Gateway -> HTTPRoute links.
 //   - Expanding Gateway listeners: Gateway -> Listener and Listener -> HTTPRoute links.
+//
+// ListenerSets, when supplied, are linked from their parent Gateways. Routes that refer to a ListenerSet in their
+// `parentRefs` field are linked from the ListenerSet, or from its Listeners when the listeners are expanded.
+//
+// The hostnames served by the listeners can be added as virtual targetables with ExpandHostnames(), linked in between
+// the listeners and the routes (Listener -> Hostname -> Route), so policies about hostnames (e.g. DNS, TLS) can target
+// them directly.
+//
+// EndpointSlices, when supplied, are linked from the Services they belong to, or from the service ports when the ports
+// are expanded. The endpoints of the EndpointSlices can be further expanded by zone with ExpandEndpointZones().
 func NewGatewayAPITopology(options ...GatewayAPITopologyOptionsFunc) (*Topology, error) {
+	topology, _, err := newGatewayAPITopology(false, options...)
+	return topology, err
+}
+
+// NewGatewayAPITopologyWithReport returns a topology of Gateway API resources, as NewGatewayAPITopology does, along with
+// a report of the orphaned and misconfigured resources supplied to it, e.g. routes whose parentRefs resolve to no Gateway
+// and backendRefs to missing Services. GatewayClasses must be supplied for the Gateways not to be reported as missing
+// them.
+func NewGatewayAPITopologyWithReport(options ...GatewayAPITopologyOptionsFunc) (*Topology, *GatewayAPITopologyReport, error) {
+	return newGatewayAPITopology(true, options...)
+}
+
+func newGatewayAPITopology(withReport bool, options ...GatewayAPITopologyOptionsFunc) (*Topology, *GatewayAPITopologyReport, error) {
 	o := &GatewayAPITopologyOptions{}
 	for _, f := range options {
 		f(o)
 	}
 
+	// parentRefs to Gateways left out of the topology are not reported as unresolved
+	var parentKeys map[string]struct{}
+	if withReport {
+		parentKeys = knownParentKeys(o.Gateways, o.ListenerSets)
+	}
+
+	if len(o.GatewayControllerNames) > 0 {
+		scopeToGatewayControllerNames(o)
+	}
+
+	if o.SkipUnprogrammedGateways {
+		skippedGateways := make(map[string]struct{})
+		o.Gateways = lo.Reject(o.Gateways, func(gateway *Gateway, _ int) bool {
+			if meta.IsStatusConditionFalse(gateway.Status.Conditions, string(gwapiv1.GatewayConditionProgrammed)) {
+				skippedGateways[gatewayKey(gateway)] = struct{}{}
+				return true
+			}
+			return false
+		})
+		o.ListenerSets = lo.Reject(o.ListenerSets, func(listenerSet *ListenerSet, _ int) bool {
+			_, skipped := skippedGateways[gatewayKeyFromListenerSet(listenerSet)]
+			return skipped
+		})
+	}
+
+	gatewaysByKey := lo.SliceToMap(o.Gateways, func(gateway *Gateway) (string, *Gateway) {
+		return gatewayKey(gateway), gateway
+	})
+	// ListenerSets whose parent Gateway is not in the topology are left out, along with their listeners
+	o.ListenerSets = lo.Filter(o.ListenerSets, func(listenerSet *ListenerSet, _ int) bool {
+		listenerSet.Gateway = gatewaysByKey[gatewayKeyFromListenerSet(listenerSet)]
+		return listenerSet.Gateway != nil
+	})
+
 	opts := []TopologyOptionsFunc{
 		WithObjects(o.Objects...),
+		WithObjects(o.ReferenceGrants...),
 		WithPolicies(o.Policies...),
+		WithTargetables(o.Namespaces...),
 		WithTargetables(o.GatewayClasses...),
 		WithTargetables(o.Gateways...),
+		WithTargetables(o.ListenerSets...),
 		WithTargetables(o.HTTPRoutes...),
 		WithTargetables(o.GRPCRoutes...),
 		WithTargetables(o.TCPRoutes...),
 		WithTargetables(o.TLSRoutes...),
 		WithTargetables(o.UDPRoutes...),
 		WithTargetables(o.Services...),
+		WithTargetables(o.EndpointSlices...),
 		WithLinks(o.Links...),
+		WithSectionExpanders(o.SectionExpanders...),
 		WithLinks(LinkGatewayClassToGatewayFunc(o.GatewayClasses)), // GatewayClass -> Gateway
+		WithLinks(LinkGatewayToListenerSetFunc(o.Gateways)),        // Gateway -> ListenerSet
+		WithLinks(
+			LinkNamespaceToGatewayFunc(o.Namespaces),   // Namespace -> Gateway
+			LinkNamespaceToHTTPRouteFunc(o.Namespaces), // Namespace -> HTTPRoute
+			LinkNamespaceToGRPCRouteFunc(o.Namespaces), // Namespace -> GRPCRoute
+			LinkNamespaceToTCPRouteFunc(o.Namespaces),  // Namespace -> TCPRoute
+			LinkNamespaceToTLSRouteFunc(o.Namespaces),  // Namespace -> TLSRoute
+			LinkNamespaceToUDPRouteFunc(o.Namespaces),  // Namespace -> UDPRoute
+		),
+		WithParallelism(o.Parallelism),
+	}
+
+	if o.RequireReferenceGrants {
+		refusedBackendRefs := refusedBackendRefsFunc(lo.GroupBy(o.ReferenceGrants, func(referenceGrant *ReferenceGrant) string {
+			return referenceGrant.Namespace
+		}))
+		for _, route := range o.HTTPRoutes {
+			route.RefusedBackendRefs = refusedBackendRefs(HTTPRouteGroupKind, route.Namespace, backendRefsFromRoute(route))
+		}
+		for _, route := range o.GRPCRoutes {
+			route.RefusedBackendRefs = refusedBackendRefs(GRPCRouteGroupKind, route.Namespace, backendRefsFromRoute(route))
+		}
+		for _, route := range o.TCPRoutes {
+			route.RefusedBackendRefs = refusedBackendRefs(TCPRouteGroupKind, route.Namespace, backendRefsFromRoute(route))
+		}
+		for _, route := range o.TLSRoutes {
+			route.RefusedBackendRefs = refusedBackendRefs(TLSRouteGroupKind, route.Namespace, backendRefsFromRoute(route))
+		}
+		for _, route := range o.UDPRoutes {
+			route.RefusedBackendRefs = refusedBackendRefs(UDPRouteGroupKind, route.Namespace, backendRefsFromRoute(route))
+		}
+	}
+
+	// expand the principal objects into their targetable sections
+	var (
+		listeners      []*Listener
+		httpRouteRules []*HTTPRouteRule
+		grpcRouteRules []*GRPCRouteRule
+		tcpRouteRules  []*TCPRouteRule
+		tlsRouteRules  []*TLSRouteRule
+		udpRouteRules  []*UDPRouteRule
+		servicePorts   []*ServicePort
+	)
+	var expansions []func()
+	if o.ExpandGatewayListeners {
+		expansions = append(expansions, func() {
+			listeners = append(lo.FlatMap(o.Gateways, ListenersFromGatewayFunc), lo.FlatMap(o.ListenerSets, ListenersFromListenerSetFunc)...)
+		})
+	}
+	if o.ExpandHTTPRouteRules {
+		expansions = append(expansions, func() { httpRouteRules = lo.FlatMap(o.HTTPRoutes, HTTPRouteRulesFromHTTPRouteFunc) })
+	}
+	if o.ExpandGRPCRouteRules {
+		expansions = append(expansions, func() { grpcRouteRules = lo.FlatMap(o.GRPCRoutes, GRPCRouteRulesFromGRPCRouteRule) })
+	}
+	if o.ExpandTCPRouteRules {
+		expansions = append(expansions, func() { tcpRouteRules = lo.FlatMap(o.TCPRoutes, TCPRouteRulesFromTCPRouteFunc) })
+	}
+	if o.ExpandTLSRouteRules {
+		expansions = append(expansions, func() { tlsRouteRules = lo.FlatMap(o.TLSRoutes, TLSRouteRulesFromTLSRouteFunc) })
+	}
+	if o.ExpandUDPRouteRules {
+		expansions = append(expansions, func() { udpRouteRules = lo.FlatMap(o.UDPRoutes, UDPRouteRulesFromUDPRouteFunc) })
+	}
+	if o.ExpandServicePorts {
+		expansions = append(expansions, func() { servicePorts = lo.FlatMap(o.Services, ServicePortsFromServiceFunc) })
+	}
+	forEach(o.Parallelism, len(expansions), func(i int) { expansions[i]() })
+
+	var (
+		httpRouteMatches []*HTTPRouteMatch
+		grpcRouteMatches []*GRPCRouteMatch
+	)
+	if o.ExpandHTTPRouteMatches {
+		httpRouteMatches = lo.FlatMap(httpRouteRules, HTTPRouteMatchesFromHTTPRouteRuleFunc)
+	}
+	if o.ExpandGRPCRouteMatches {
+		grpcRouteMatches = lo.FlatMap(grpcRouteRules, GRPCRouteMatchesFromGRPCRouteRuleFunc)
+	}
+
+	var listenerAcceptsRoute []func(*Listener, Object) bool
+	if o.EnforceAllowedRoutes {
+		listenerAcceptsRoute = append(listenerAcceptsRoute, ListenerAllowsRouteFunc(o.Namespaces))
+	}
+	if o.EnforceHostnames {
+		listenerAcceptsRoute = append(listenerAcceptsRoute, func(listener *Listener, route Object) bool {
+			_, ok := EffectiveHostnames(listener, route)
+			return ok
+		})
+	}
+	if o.TrustRouteParentStatus {
+		listenerAcceptsRoute = append(listenerAcceptsRoute, routeParentStatusAcceptsFunc(o.GatewayClasses, o.RouteParentControllerNames))
+	}
+	routeAttachmentLinks := func(links ...LinkFunc) []LinkFunc { return links }
+	if len(listenerAcceptsRoute) > 0 {
+		accepts := func(listener *Listener, route Object) bool {
+			return lo.EveryBy(listenerAcceptsRoute, func(f func(*Listener, Object) bool) bool { return f(listener, route) })
+		}
+		routeAttachmentLinks = func(links ...LinkFunc) []LinkFunc {
+			return lo.Map(links, func(link LinkFunc, _ int) LinkFunc {
+				return filterLinkFunc(link, func(parent, child Object) bool {
+					switch p := parent.(type) {
+					case *Listener:
+						return accepts(p, child)
+					case *Gateway:
+						return lo.ContainsBy(ListenersFromGatewayFunc(p, 0), func(listener *Listener) bool {
+							return routeRefersToListener(child, listener) && accepts(listener, child)
+						})
+					case *ListenerSet:
+						return lo.ContainsBy(ListenersFromListenerSetFunc(p, 0), func(listener *Listener) bool {
+							return routeRefersToListener(child, listener) && accepts(listener, child)
+						})
+					}
+					return true
+				})
+			})
+		}
+	}
+
+	var report *GatewayAPITopologyReport
+	if withReport {
+		allListeners := listeners
+		if !o.ExpandGatewayListeners {
+			allListeners = append(lo.FlatMap(o.Gateways, ListenersFromGatewayFunc), lo.FlatMap(o.ListenerSets, ListenersFromListenerSetFunc)...)
+		}
+		report = gatewayAPITopologyReport(o, parentKeys, allListeners, routeAttachmentLinks(
+			LinkListenerToHTTPRouteFunc(o.Gateways, allListeners),
+			LinkListenerToGRPCRouteFunc(o.Gateways, allListeners),
+			LinkListenerToTCPRouteFunc(o.Gateways, allListeners),
+			LinkListenerToTLSRouteFunc(o.Gateways, allListeners),
+			LinkListenerToUDPRouteFunc(o.Gateways, allListeners),
+		))
 	}
 
 	if o.ExpandGatewayListeners {
-		listeners := lo.FlatMap(o.Gateways, ListenersFromGatewayFunc)
 		opts = append(opts, WithTargetables(listeners...))
 		opts = append(opts, WithLinks(
-			LinkGatewayToListenerFunc(),                        // Gateway -> Listener
+			LinkGatewayToListenerFunc(),     // Gateway -> Listener
+			LinkListenerSetToListenerFunc(), // ListenerSet -> Listener
+		))
+		listenerToRouteLinks := routeAttachmentLinks(
 			LinkListenerToHTTPRouteFunc(o.Gateways, listeners), // Listener -> HTTPRoute
 			LinkListenerToGRPCRouteFunc(o.Gateways, listeners), // Listener -> GRPCRoute
 			LinkListenerToTCPRouteFunc(o.Gateways, listeners),  // Listener -> TCPRoute
 			LinkListenerToTLSRouteFunc(o.Gateways, listeners),  // Listener -> TLSRoute
 			LinkListenerToUDPRouteFunc(o.Gateways, listeners),  // Listener -> UDPRoute
-		))
+		)
+		if o.ExpandHostnames {
+			hostnames, hostnameToRouteLinks, listenerToRouteLinksLeft := expandHostnames(listeners, listenerToRouteLinks, map[schema.GroupKind][]Object{
+				HTTPRouteGroupKind: lo.Map(o.HTTPRoutes, asObject[*HTTPRoute]),
+				GRPCRouteGroupKind: lo.Map(o.GRPCRoutes, asObject[*GRPCRoute]),
+				TCPRouteGroupKind:  lo.Map(o.TCPRoutes, asObject[*TCPRoute]),
+				TLSRouteGroupKind:  lo.Map(o.TLSRoutes, asObject[*TLSRoute]),
+				UDPRouteGroupKind:  lo.Map(o.UDPRoutes, asObject[*UDPRoute]),
+			})
+			opts = append(opts, WithTargetables(hostnames...))
+			opts = append(opts, WithLinks(LinkListenerToHostnameFunc())) // Listener -> Hostname
+			opts = append(opts, WithLinks(hostnameToRouteLinks...))      // Hostname -> Route
+			listenerToRouteLinks = listenerToRouteLinksLeft
+		}
+		opts = append(opts, WithLinks(listenerToRouteLinks...))
 	} else {
-		opts = append(opts, WithLinks(
-			LinkGatewayToHTTPRouteFunc(o.Gateways), // Gateway -> HTTPRoute
-			LinkGatewayToGRPCRouteFunc(o.Gateways), // Gateway -> GRPCRoute
-			LinkGatewayToTCPRouteFunc(o.Gateways),  // Gateway -> TCPRoute
-			LinkGatewayToTLSRouteFunc(o.Gateways),  // Gateway -> TLSRoute
-			LinkGatewayToUDPRouteFunc(o.Gateways),  // Gateway -> UDPRoute
-		))
+		opts = append(opts, WithLinks(routeAttachmentLinks(
+			LinkGatewayToHTTPRouteFunc(o.Gateways),         // Gateway -> HTTPRoute
+			LinkGatewayToGRPCRouteFunc(o.Gateways),         // Gateway -> GRPCRoute
+			LinkGatewayToTCPRouteFunc(o.Gateways),          // Gateway -> TCPRoute
+			LinkGatewayToTLSRouteFunc(o.Gateways),          // Gateway -> TLSRoute
+			LinkGatewayToUDPRouteFunc(o.Gateways),          // Gateway -> UDPRoute
+			LinkListenerSetToHTTPRouteFunc(o.ListenerSets), // ListenerSet -> HTTPRoute
+			LinkListenerSetToGRPCRouteFunc(o.ListenerSets), // ListenerSet -> GRPCRoute
+			LinkListenerSetToTCPRouteFunc(o.ListenerSets),  // ListenerSet -> TCPRoute
+			LinkListenerSetToTLSRouteFunc(o.ListenerSets),  // ListenerSet -> TLSRoute
+			LinkListenerSetToUDPRouteFunc(o.ListenerSets),  // ListenerSet -> UDPRoute
+		)...))
 	}
 
 	if o.ExpandHTTPRouteRules {
-		httpRouteRules := lo.FlatMap(o.HTTPRoutes, HTTPRouteRulesFromHTTPRouteFunc)
 		opts = append(opts, WithTargetables(httpRouteRules...))
 		opts = append(opts, WithLinks(LinkHTTPRouteToHTTPRouteRuleFunc())) // HTTPRoute -> HTTPRouteRule
 
+		if o.ExpandHTTPRouteMatches {
+			opts = append(opts, WithTargetables(httpRouteMatches...))
+			opts = append(opts, WithLinks(LinkHTTPRouteRuleToHTTPRouteMatchFunc())) // HTTPRouteRule -> HTTPRouteMatch
+		}
+
 		if o.ExpandServicePorts {
 			opts = append(opts, WithLinks(
 				LinkHTTPRouteRuleToServicePortFunc(httpRouteRules),   // HTTPRouteRule -> ServicePort
@@ -263,10 +691,14 @@ func NewGatewayAPITopology(options ...GatewayAPITopologyOptionsFunc) (*Topology,
 	}
 
 	if o.ExpandGRPCRouteRules {
-		grpcRouteRules := lo.FlatMap(o.GRPCRoutes, GRPCRouteRulesFromGRPCRouteRule)
 		opts = append(opts, WithTargetables(grpcRouteRules...))
 		opts = append(opts, WithLinks(LinkGRPCRouteToGRPCRouteRuleFunc())) // GRPCRoute -> GRPCRouteRule
 
+		if o.ExpandGRPCRouteMatches {
+			opts = append(opts, WithTargetables(grpcRouteMatches...))
+			opts = append(opts, WithLinks(LinkGRPCRouteRuleToGRPCRouteMatchFunc())) // GRPCRouteRule -> GRPCRouteMatch
+		}
+
 		if o.ExpandServicePorts {
 			opts = append(opts, WithLinks(
 				LinkGRPCRouteRuleToServicePortFunc(grpcRouteRules),   // GRPCRouteRule -> ServicePort
@@ -287,7 +719,6 @@ func NewGatewayAPITopology(options ...GatewayAPITopologyOptionsFunc) (*Topology,
 	}
 
 	if o.ExpandTCPRouteRules {
-		tcpRouteRules := lo.FlatMap(o.TCPRoutes, TCPRouteRulesFromTCPRouteFunc)
 		opts = append(opts, WithTargetables(tcpRouteRules...))
 		opts = append(opts, WithLinks(LinkTCPRouteToTCPRouteRuleFunc())) // TCPRoute - TCPRouteRules
 
@@ -311,7 +742,6 @@ func NewGatewayAPITopology(options ...GatewayAPITopologyOptionsFunc) (*Topology,
 	}
 
 	if o.ExpandTLSRouteRules {
-		tlsRouteRules := lo.FlatMap(o.TLSRoutes, TLSRouteRulesFromTLSRouteFunc)
 		opts = append(opts, WithTargetables(tlsRouteRules...))
 		opts = append(opts, WithLinks(LinkTLSRouteToTLSRouteRuleFunc()))
 
@@ -335,7 +765,6 @@ func NewGatewayAPITopology(options ...GatewayAPITopologyOptionsFunc) (*Topology,
 	}
 
 	if o.ExpandUDPRouteRules {
-		udpRouteRules := lo.FlatMap(o.UDPRoutes, UDPRouteRulesFromUDPRouteFunc)
 		opts = append(opts, WithTargetables(udpRouteRules...))
 		opts = append(opts, WithLinks(LinkUDPRouteToUDPRouteRuleFunc()))
 
@@ -359,16 +788,159 @@ func NewGatewayAPITopology(options ...GatewayAPITopologyOptionsFunc) (*Topology,
 	}
 
 	if o.ExpandServicePorts {
-		servicePorts := lo.FlatMap(o.Services, ServicePortsFromServiceFunc)
 		opts = append(opts, WithTargetables(servicePorts...))
 		opts = append(opts, WithLinks(LinkServiceToServicePortFunc())) // Service -> ServicePort
 	}
 
+	if o.ExpandServicePorts {
+		opts = append(opts, WithLinks(
+			LinkServiceToEndpointSliceFunc(o.Services, true), // Service -> EndpointSlice
+			LinkServicePortToEndpointSliceFunc(servicePorts), // ServicePort -> EndpointSlice
+		))
+	} else {
+		opts = append(opts, WithLinks(LinkServiceToEndpointSliceFunc(o.Services, false))) // Service -> EndpointSlice
+	}
+
+	if o.ExpandEndpointZones {
+		opts = append(opts, WithTargetables(lo.FlatMap(o.EndpointSlices, EndpointZonesFromEndpointSliceFunc)...))
+		opts = append(opts, WithLinks(LinkEndpointSliceToEndpointZoneFunc())) // EndpointSlice -> EndpointZone
+	}
+
+	if o.MeshRoutes {
+		opts = append(opts, WithLinks(
+			LinkServiceToHTTPRouteFunc(o.Services, o.ExpandServicePorts), // Service -> HTTPRoute
+			LinkServiceToGRPCRouteFunc(o.Services, o.ExpandServicePorts), // Service -> GRPCRoute
+		))
+		if o.ExpandServicePorts {
+			opts = append(opts, WithLinks(
+				LinkServicePortToHTTPRouteFunc(servicePorts), // ServicePort -> HTTPRoute
+				LinkServicePortToGRPCRouteFunc(servicePorts), // ServicePort -> GRPCRoute
+			))
+		}
+	}
+
+	// link the backends of the registered kinds from the routes, or from the route rules when expanded
+	backendParents := []lo.Tuple2[schema.GroupKind, []Object]{
+		lo.T2(HTTPRouteGroupKind, lo.Map(o.HTTPRoutes, asObject[*HTTPRoute])),
+		lo.T2(GRPCRouteGroupKind, lo.Map(o.GRPCRoutes, asObject[*GRPCRoute])),
+		lo.T2(TCPRouteGroupKind, lo.Map(o.TCPRoutes, asObject[*TCPRoute])),
+		lo.T2(TLSRouteGroupKind, lo.Map(o.TLSRoutes, asObject[*TLSRoute])),
+		lo.T2(UDPRouteGroupKind, lo.Map(o.UDPRoutes, asObject[*UDPRoute])),
+	}
+	if o.ExpandHTTPRouteRules {
+		backendParents[0] = lo.T2(HTTPRouteRuleGroupKind, lo.Map(httpRouteRules, asObject[*HTTPRouteRule]))
+	}
+	if o.ExpandGRPCRouteRules {
+		backendParents[1] = lo.T2(GRPCRouteRuleGroupKind, lo.Map(grpcRouteRules, asObject[*GRPCRouteRule]))
+	}
+	if o.ExpandTCPRouteRules {
+		backendParents[2] = lo.T2(TCPRouteRuleGroupKind, lo.Map(tcpRouteRules, asObject[*TCPRouteRule]))
+	}
+	if o.ExpandTLSRouteRules {
+		backendParents[3] = lo.T2(TLSRouteRuleGroupKind, lo.Map(tlsRouteRules, asObject[*TLSRouteRule]))
+	}
+	if o.ExpandUDPRouteRules {
+		backendParents[4] = lo.T2(UDPRouteRuleGroupKind, lo.Map(udpRouteRules, asObject[*UDPRouteRule]))
+	}
+	for _, backendKind := range o.BackendKinds {
+		opts = append(opts, WithTargetables(backendKind.Backends...))
+		if backendKind.Ports != nil {
+			opts = append(opts, WithTargetables(lo.FlatMap(backendKind.Backends, func(backend Targetable, _ int) []*BackendPort {
+				return backendKind.Ports(backend)
+			})...))
+			opts = append(opts, WithLinks(LinkBackendToBackendPortFunc(backendKind))) // Backend -> BackendPort
+		}
+		for _, parents := range backendParents {
+			opts = append(opts, WithLinks(LinkToBackendFunc(parents.A, parents.B, backendKind))) // Route|RouteRule -> Backend
+			if backendKind.Ports != nil {
+				opts = append(opts, WithLinks(LinkToBackendPortFunc(parents.A, parents.B, backendKind))) // Route|RouteRule -> BackendPort
+			}
+		}
+	}
+
+	if o.MeshRoutes {
+		opts = append(opts, skipMeshParentBackends()) // no Route|RouteRule -> parent Service|ServicePort
+	}
+
 	if o.allowTopologyLoops {
 		opts = append(opts, AllowLoops())
 	}
 
-	return NewTopology(opts...)
+	topology, err := NewTopology(opts...)
+	return topology, report, err
+}
+
+// scopeToGatewayControllerNames leaves out of the options the GatewayClasses of controllers other than the ones in the
+// options, the Gateways of those GatewayClasses and their ListenerSets, the routes that do not attach to any of the
+// remaining Gateways and ListenerSets (mesh routes excepted, when enabled), and the Services and other backends
+// referred only by the routes left out
+func scopeToGatewayControllerNames(o *GatewayAPITopologyOptions) {
+	o.GatewayClasses = lo.Filter(o.GatewayClasses, func(gatewayClass *GatewayClass, _ int) bool {
+		return lo.Contains(o.GatewayControllerNames, gatewayClass.Spec.ControllerName)
+	})
+	gatewayClassNames := lo.SliceToMap(o.GatewayClasses, func(gatewayClass *GatewayClass) (string, struct{}) {
+		return gatewayClass.Name, struct{}{}
+	})
+	o.Gateways = lo.Filter(o.Gateways, func(gateway *Gateway, _ int) bool {
+		_, ok := gatewayClassNames[string(gateway.Spec.GatewayClassName)]
+		return ok
+	})
+	parentKeys := lo.SliceToMap(o.Gateways, func(gateway *Gateway) (string, struct{}) {
+		return gatewayKey(gateway), struct{}{}
+	})
+	o.ListenerSets = lo.Filter(o.ListenerSets, func(listenerSet *ListenerSet, _ int) bool {
+		_, ok := parentKeys[gatewayKeyFromListenerSet(listenerSet)]
+		return ok
+	})
+	for _, listenerSet := range o.ListenerSets {
+		parentKeys[listenerSetKey(listenerSet)] = struct{}{}
+	}
+
+	keptBackends := make(map[string]struct{})
+	leftOutBackends := make(map[string]struct{})
+	scope := func(route Object, _ int) bool {
+		inScope := lo.ContainsBy(parentRefsFromRoute(route), func(parentRef gwapiv1.ParentReference) bool {
+			// mesh routes are not attached to any GatewayClass
+			if _, mesh := serviceKeyFromParentRef(parentRef, route.GetNamespace()); mesh && o.MeshRoutes {
+				return true
+			}
+			key, ok := parentKeyFromParentRef(parentRef, route.GetNamespace())
+			_, found := parentKeys[key]
+			return ok && found
+		})
+		backends := leftOutBackends
+		if inScope {
+			backends = keptBackends
+		}
+		for _, backendRef := range backendRefsFromRoute(route) {
+			backends[backendRefKey(backendRef, route.GetNamespace())] = struct{}{}
+		}
+		return inScope
+	}
+	o.HTTPRoutes = lo.Filter(o.HTTPRoutes, func(route *HTTPRoute, i int) bool { return scope(route, i) })
+	o.GRPCRoutes = lo.Filter(o.GRPCRoutes, func(route *GRPCRoute, i int) bool { return scope(route, i) })
+	o.TCPRoutes = lo.Filter(o.TCPRoutes, func(route *TCPRoute, i int) bool { return scope(route, i) })
+	o.TLSRoutes = lo.Filter(o.TLSRoutes, func(route *TLSRoute, i int) bool { return scope(route, i) })
+	o.UDPRoutes = lo.Filter(o.UDPRoutes, func(route *UDPRoute, i int) bool { return scope(route, i) })
+
+	leftOutOnly := func(key string) bool {
+		_, kept := keptBackends[key]
+		_, leftOut := leftOutBackends[key]
+		return leftOut && !kept
+	}
+	o.Services = lo.Reject(o.Services, func(service *Service, _ int) bool {
+		return leftOutOnly(serviceKey(service))
+	})
+	o.EndpointSlices = lo.Reject(o.EndpointSlices, func(endpointSlice *EndpointSlice, _ int) bool {
+		key, ok := serviceKeyFromEndpointSlice(endpointSlice)
+		return ok && leftOutOnly(key)
+	})
+	for i := range o.BackendKinds {
+		backendKind := o.BackendKinds[i]
+		o.BackendKinds[i].Backends = lo.Reject(backendKind.Backends, func(backend Targetable, _ int) bool {
+			return leftOutOnly(backendKindKey(backendKind, backend))
+		})
+	}
 }
 
 // ListenersFromGatewayFunc returns a list of targetable listeners from a targetable gateway.
@@ -381,6 +953,24 @@ func ListenersFromGatewayFunc(gateway *Gateway, _ int) []*Listener {
 	})
 }
 
+// ListenersFromListenerSetFunc returns a list of targetable listeners from a targetable listener set.
+func ListenersFromListenerSetFunc(listenerSet *ListenerSet, _ int) []*Listener {
+	return lo.Map(listenerSet.Spec.Listeners, func(entry gwapiv1.ListenerEntry, _ int) *Listener {
+		return &Listener{
+			Listener: &gwapiv1.Listener{
+				Name:          entry.Name,
+				Hostname:      entry.Hostname,
+				Port:          entry.Port,
+				Protocol:      entry.Protocol,
+				TLS:           entry.TLS,
+				AllowedRoutes: entry.AllowedRoutes,
+			},
+			Gateway:     listenerSet.Gateway,
+			ListenerSet: listenerSet,
+		}
+	})
+}
+
 // HTTPRouteRulesFromHTTPRouteFunc returns a list of targetable HTTPRouteRules from a targetable HTTPRoute.
 func HTTPRouteRulesFromHTTPRouteFunc(httpRoute *HTTPRoute, _ int) []*HTTPRouteRule {
 	return lo.Map(httpRoute.Spec.Rules, func(rule gwapiv1.HTTPRouteRule, i int) *HTTPRouteRule {
@@ -417,6 +1007,34 @@ func GRPCRouteRulesFromGRPCRouteRule(grpcRoute *GRPCRoute, _ int) []*GRPCRouteRu
 	})
 }
 
+// HTTPRouteMatchesFromHTTPRouteRuleFunc returns a list of targetable HTTPRouteMatches from a targetable HTTPRouteRule.
+func HTTPRouteMatchesFromHTTPRouteRuleFunc(httpRouteRule *HTTPRouteRule, _ int) []*HTTPRouteMatch {
+	return lo.Map(httpRouteRule.Matches, func(match gwapiv1.HTTPRouteMatch, i int) *HTTPRouteMatch {
+		return &HTTPRouteMatch{
+			HTTPRouteMatch: &match,
+			HTTPRouteRule:  httpRouteRule,
+			Name:           routeMatchSectionName(httpRouteRule.Name, i),
+		}
+	})
+}
+
+// GRPCRouteMatchesFromGRPCRouteRuleFunc returns a list of targetable GRPCRouteMatches from a targetable GRPCRouteRule.
+func GRPCRouteMatchesFromGRPCRouteRuleFunc(grpcRouteRule *GRPCRouteRule, _ int) []*GRPCRouteMatch {
+	return lo.Map(grpcRouteRule.Matches, func(match gwapiv1.GRPCRouteMatch, i int) *GRPCRouteMatch {
+		return &GRPCRouteMatch{
+			GRPCRouteMatch: &match,
+			GRPCRouteRule:  grpcRouteRule,
+			Name:           routeMatchSectionName(grpcRouteRule.Name, i),
+		}
+	})
+}
+
+// routeMatchSectionName returns the section name of the match at the given position of a route rule, which is the name
+// of the rule followed by the 1-based position of the match
+func routeMatchSectionName(ruleName gwapiv1.SectionName, i int) gwapiv1.SectionName {
+	return gwapiv1.SectionName(fmt.Sprintf("%s.match-%d", ruleName, i+1))
+}
+
 // TCPRouteRulesFromTCPRouteFunc returns a list of targetable TCPRouteRules from a targetable TCPRoute.
 func TCPRouteRulesFromTCPRouteFunc(tcpRoute *TCPRoute, _ int) []*TCPRouteRule {
 	return lo.Map(tcpRoute.Spec.Rules, func(rule gwapiv1.TCPRouteRule, i int) *TCPRouteRule {
@@ -471,6 +1089,27 @@ func UDPRouteRulesFromUDPRouteFunc(udpRoute *UDPRoute, _ int) []*UDPRouteRule {
 	})
 }
 
+// EndpointZonesFromEndpointSliceFunc returns a list of targetable endpoint zones from a targetable EndpointSlice.
+// The endpoints are grouped by zone, in the order the zones first appear in the EndpointSlice.
+func EndpointZonesFromEndpointSliceFunc(endpointSlice *EndpointSlice, _ int) []*EndpointZone {
+	var endpointZones []*EndpointZone
+	endpointZonesByName := make(map[string]*EndpointZone)
+	for _, endpoint := range endpointSlice.Endpoints {
+		zone := ptr.Deref(endpoint.Zone, "")
+		if zone == "" {
+			continue
+		}
+		endpointZone, ok := endpointZonesByName[zone]
+		if !ok {
+			endpointZone = &EndpointZone{Zone: zone, EndpointSlice: endpointSlice}
+			endpointZonesByName[zone] = endpointZone
+			endpointZones = append(endpointZones, endpointZone)
+		}
+		endpointZone.Endpoints = append(endpointZone.Endpoints, endpoint)
+	}
+	return endpointZones
+}
+
 // ServicePortsFromServiceFunc returns a list of targetable service ports from a targetable Service.
 func ServicePortsFromServiceFunc(service *Service, _ int) []*ServicePort {
 	return lo.Map(service.Spec.Ports, func(port core.ServicePort, _ int) *ServicePort {
@@ -484,100 +1123,157 @@ func ServicePortsFromServiceFunc(service *Service, _ int) []*ServicePort {
 // LinkGatewayClassToGatewayFunc returns a link function that teaches a topology how to link Gateways from known
 // GatewayClasses, based on the Gateway's `gatewayClassName` field.
 func LinkGatewayClassToGatewayFunc(gatewayClasses []*GatewayClass) LinkFunc {
-	return LinkFunc{
-		From: GatewayClassGroupKind,
-		To:   GatewayGroupKind,
-		Func: func(child Object) []Object {
+	return IndexedLinkFunc(GatewayClassGroupKind, GatewayGroupKind, gatewayClasses,
+		func(gatewayClass *GatewayClass) []string {
+			return []string{gatewayClass.Name}
+		},
+		func(child Object) []string {
 			gateway := child.(*Gateway)
-			gatewayClass, ok := lo.Find(gatewayClasses, func(gc *GatewayClass) bool {
-				return gc.Name == string(gateway.Spec.GatewayClassName)
-			})
-			if ok {
-				return []Object{gatewayClass}
-			}
-			return nil
+			return []string{string(gateway.Spec.GatewayClassName)}
 		},
-	}
+	)
+}
+
+// LinkNamespaceToGatewayFunc returns a link function that teaches a topology how to link Gateways from known
+// Namespaces, based on the Gateway's namespace.
+func LinkNamespaceToGatewayFunc(namespaces []*Namespace) LinkFunc {
+	return linkNamespaceToObjectFunc(namespaces, GatewayGroupKind)
+}
+
+// LinkNamespaceToHTTPRouteFunc returns a link function that teaches a topology how to link HTTPRoutes from known
+// Namespaces, based on the HTTPRoute's namespace.
+func LinkNamespaceToHTTPRouteFunc(namespaces []*Namespace) LinkFunc {
+	return linkNamespaceToObjectFunc(namespaces, HTTPRouteGroupKind)
+}
+
+// LinkNamespaceToGRPCRouteFunc returns a link function that teaches a topology how to link GRPCRoutes from known
+// Namespaces, based on the GRPCRoute's namespace.
+func LinkNamespaceToGRPCRouteFunc(namespaces []*Namespace) LinkFunc {
+	return linkNamespaceToObjectFunc(namespaces, GRPCRouteGroupKind)
+}
+
+// LinkNamespaceToTCPRouteFunc returns a link function that teaches a topology how to link TCPRoutes from known
+// Namespaces, based on the TCPRoute's namespace.
+func LinkNamespaceToTCPRouteFunc(namespaces []*Namespace) LinkFunc {
+	return linkNamespaceToObjectFunc(namespaces, TCPRouteGroupKind)
+}
+
+// LinkNamespaceToTLSRouteFunc returns a link function that teaches a topology how to link TLSRoutes from known
+// Namespaces, based on the TLSRoute's namespace.
+func LinkNamespaceToTLSRouteFunc(namespaces []*Namespace) LinkFunc {
+	return linkNamespaceToObjectFunc(namespaces, TLSRouteGroupKind)
+}
+
+// LinkNamespaceToUDPRouteFunc returns a link function that teaches a topology how to link UDPRoutes from known
+// Namespaces, based on the UDPRoute's namespace.
+func LinkNamespaceToUDPRouteFunc(namespaces []*Namespace) LinkFunc {
+	return linkNamespaceToObjectFunc(namespaces, UDPRouteGroupKind)
+}
+
+func linkNamespaceToObjectFunc(namespaces []*Namespace, to schema.GroupKind) LinkFunc {
+	return IndexedLinkFunc(NamespaceGroupKind, to, namespaces,
+		func(namespace *Namespace) []string {
+			return []string{namespace.Name}
+		},
+		func(child Object) []string {
+			return []string{child.GetNamespace()}
+		},
+	)
 }
 
 // LinkGatewayToHTTPRouteFunc returns a link function that teaches a topology how to link HTTPRoutes from known
 // Gateways, based on the HTTPRoute's `parentRefs` field.
+// The Gateway is linked by name, regardless of the `sectionName` and `port` fields of the parent reference.
 func LinkGatewayToHTTPRouteFunc(gateways []*Gateway) LinkFunc {
-	return LinkFunc{
-		From: GatewayGroupKind,
-		To:   HTTPRouteGroupKind,
-		Func: func(child Object) []Object {
-			httpRoute := child.(*HTTPRoute)
-			return lo.FilterMap(httpRoute.Spec.ParentRefs, findGatewayFromParentRefFunc(gateways, httpRoute.Namespace))
-		},
-	}
+	return IndexedLinkFunc(GatewayGroupKind, HTTPRouteGroupKind, gateways, func(gateway *Gateway) []string {
+		return []string{gatewayKey(gateway)}
+	}, func(child Object) []string {
+		httpRoute := child.(*HTTPRoute)
+		return lo.FilterMap(httpRoute.Spec.ParentRefs, func(parentRef gwapiv1.ParentReference, _ int) (string, bool) {
+			return gatewayKeyFromParentRef(parentRef, httpRoute.Namespace)
+		})
+	})
 }
 
 // LinkGatewayToGRPCRouteFunc returns a link function that teaches a topology how to link GRPCRoute's from known
 // Gateway's, based on the GRPCRoute's `parentRefs` field.
+// The Gateway is linked by name, regardless of the `sectionName` and `port` fields of the parent reference.
 func LinkGatewayToGRPCRouteFunc(gateways []*Gateway) LinkFunc {
-	return LinkFunc{
-		From: GatewayGroupKind,
-		To:   GRPCRouteGroupKind,
-		Func: func(child Object) []Object {
-			grpcRoute := child.(*GRPCRoute)
-			return lo.FilterMap(grpcRoute.Spec.ParentRefs, findGatewayFromParentRefFunc(gateways, grpcRoute.Namespace))
-		},
-	}
+	return IndexedLinkFunc(GatewayGroupKind, GRPCRouteGroupKind, gateways, func(gateway *Gateway) []string {
+		return []string{gatewayKey(gateway)}
+	}, func(child Object) []string {
+		grpcRoute := child.(*GRPCRoute)
+		return lo.FilterMap(grpcRoute.Spec.ParentRefs, func(parentRef gwapiv1.ParentReference, _ int) (string, bool) {
+			return gatewayKeyFromParentRef(parentRef, grpcRoute.Namespace)
+		})
+	})
 }
 
 // LinkGatewayToTCPRouteFunc returns a link function that teaches a topology how to link TCPRoute's from known
 // Gateway's, based on the TCPRoute's `parentRefs` field.
+// The Gateway is linked by name, regardless of the `sectionName` and `port` fields of the parent reference.
 func LinkGatewayToTCPRouteFunc(gateways []*Gateway) LinkFunc {
-	return LinkFunc{
-		From: GatewayGroupKind,
-		To:   TCPRouteGroupKind,
-		Func: func(child Object) []Object {
-			tcpRoute := child.(*TCPRoute)
-			return lo.FilterMap(tcpRoute.Spec.ParentRefs, findGatewayFromParentRefFunc(gateways, tcpRoute.Namespace))
-		},
-	}
+	return IndexedLinkFunc(GatewayGroupKind, TCPRouteGroupKind, gateways, func(gateway *Gateway) []string {
+		return []string{gatewayKey(gateway)}
+	}, func(child Object) []string {
+		tcpRoute := child.(*TCPRoute)
+		return lo.FilterMap(tcpRoute.Spec.ParentRefs, func(parentRef gwapiv1.ParentReference, _ int) (string, bool) {
+			return gatewayKeyFromParentRef(parentRef, tcpRoute.Namespace)
+		})
+	})
 }
 
 // LinkGatewayToTLSRouteFunc returns a link function that teaches a topology how to link TLSRoute's from known
 // Gateway's, based on the TLSRoute's `parentRefs` field.
+// The Gateway is linked by name, regardless of the `sectionName` and `port` fields of the parent reference.
 func LinkGatewayToTLSRouteFunc(gateways []*Gateway) LinkFunc {
-	return LinkFunc{
-		From: GatewayGroupKind,
-		To:   TLSRouteGroupKind,
-		Func: func(child Object) []Object {
-			tlsRoute := child.(*TLSRoute)
-			return lo.FilterMap(tlsRoute.Spec.ParentRefs, findGatewayFromParentRefFunc(gateways, tlsRoute.Namespace))
-		},
-	}
+	return IndexedLinkFunc(GatewayGroupKind, TLSRouteGroupKind, gateways, func(gateway *Gateway) []string {
+		return []string{gatewayKey(gateway)}
+	}, func(child Object) []string {
+		tlsRoute := child.(*TLSRoute)
+		return lo.FilterMap(tlsRoute.Spec.ParentRefs, func(parentRef gwapiv1.ParentReference, _ int) (string, bool) {
+			return gatewayKeyFromParentRef(parentRef, tlsRoute.Namespace)
+		})
+	})
 }
 
 // LinkGatewayToUDPRouteFunc returns a link function that teaches a topology how to link UDPRoute's from known
 // Gateway's, based on the UDPRoute's `parentRefs` field.
+// The Gateway is linked by name, regardless of the `sectionName` and `port` fields of the parent reference.
 func LinkGatewayToUDPRouteFunc(gateways []*Gateway) LinkFunc {
-	return LinkFunc{
-		From: GatewayGroupKind,
-		To:   UDPRouteGroupKind,
-		Func: func(child Object) []Object {
-			udpRoute := child.(*UDPRoute)
-			return lo.FilterMap(udpRoute.Spec.ParentRefs, findGatewayFromParentRefFunc(gateways, udpRoute.Namespace))
-		},
+	return IndexedLinkFunc(GatewayGroupKind, UDPRouteGroupKind, gateways, func(gateway *Gateway) []string {
+		return []string{gatewayKey(gateway)}
+	}, func(child Object) []string {
+		udpRoute := child.(*UDPRoute)
+		return lo.FilterMap(udpRoute.Spec.ParentRefs, func(parentRef gwapiv1.ParentReference, _ int) (string, bool) {
+			return gatewayKeyFromParentRef(parentRef, udpRoute.Namespace)
+		})
+	})
+}
+
+// gatewayKeyFromParentRef returns the index key of the Gateway a parent reference points to, regardless of section
+// name and port
+func gatewayKeyFromParentRef(parentRef gwapiv1.ParentReference, routeNamespace string) (string, bool) {
+	parentRefGroup := ptr.Deref(parentRef.Group, gwapiv1.GroupName)
+	parentRefKind := ptr.Deref(parentRef.Kind, "Gateway")
+	if parentRefGroup != gwapiv1.GroupName || parentRefKind != "Gateway" {
+		return "", false
 	}
+	gatewayNamespace := string(ptr.Deref(parentRef.Namespace, gwapiv1.Namespace(routeNamespace)))
+	return namespacedName(gatewayNamespace, string(parentRef.Name)), true
 }
 
-// findGatewayFromParentRefFunc is a common function to find a Gateway from a xRoute's `parentRef` field
-func findGatewayFromParentRefFunc(gateways []*Gateway, routeNamespace string) func(parentRef gwapiv1.ParentReference, _ int) (Object, bool) {
-	return func(parentRef gwapiv1.ParentReference, _ int) (Object, bool) {
-		parentRefGroup := ptr.Deref(parentRef.Group, gwapiv1.GroupName)
-		parentRefKind := ptr.Deref(parentRef.Kind, "Gateway")
-		if parentRefGroup != gwapiv1.GroupName || parentRefKind != "Gateway" {
-			return nil, false
-		}
-		gatewayNamespace := string(ptr.Deref(parentRef.Namespace, gwapiv1.Namespace(routeNamespace)))
-		return lo.Find(gateways, func(g *Gateway) bool {
-			return g.Namespace == gatewayNamespace && g.Name == string(parentRef.Name)
-		})
+// parentKeyFromParentRef returns the index key of the Gateway or ListenerSet a parent reference points to, regardless
+// of section name and port
+func parentKeyFromParentRef(parentRef gwapiv1.ParentReference, routeNamespace string) (string, bool) {
+	if key, ok := gatewayKeyFromParentRef(parentRef, routeNamespace); ok {
+		return key, true
 	}
+	return listenerSetKeyFromParentRef(parentRef, routeNamespace)
+}
+
+func gatewayKey(gateway *Gateway) string {
+	return namespacedName(gateway.Namespace, gateway.Name)
 }
 
 // LinkGatewayToListenerFunc returns a link function that teaches a topology how to link gateway Listeners from the
@@ -588,114 +1284,523 @@ func LinkGatewayToListenerFunc() LinkFunc {
 		To:   ListenerGroupKind,
 		Func: func(child Object) []Object {
 			listener := child.(*Listener)
+			if listener.ListenerSet != nil || listener.Gateway == nil {
+				return nil
+			}
 			return []Object{listener.Gateway}
 		},
 	}
 }
 
-// LinkListenerToHTTPRouteFunc returns a link function that teaches a topology how to link HTTPRoutes from known
-// Gateways and gateway Listeners, based on the HTTPRoute's `parentRefs` field.
-// The function links a specific Listener of a Gateway to the HTTPRoute when the `sectionName` field of the parent
-// reference is present, otherwise all Listeners of the parent Gateway are linked to the HTTPRoute.
-func LinkListenerToHTTPRouteFunc(gateways []*Gateway, listeners []*Listener) LinkFunc {
+// LinkGatewayToListenerSetFunc returns a link function that teaches a topology how to link ListenerSets from known
+// Gateways, based on the ListenerSet's `parentRef` field.
+func LinkGatewayToListenerSetFunc(gateways []*Gateway) LinkFunc {
+	return IndexedLinkFunc(GatewayGroupKind, ListenerSetGroupKind, gateways, func(gateway *Gateway) []string {
+		return []string{gatewayKey(gateway)}
+	}, func(child Object) []string {
+		return []string{gatewayKeyFromListenerSet(child.(*ListenerSet))}
+	})
+}
+
+// LinkListenerSetToListenerFunc returns a link function that teaches a topology how to link Listeners from the
+// ListenerSets that define them.
+func LinkListenerSetToListenerFunc() LinkFunc {
 	return LinkFunc{
-		From: ListenerGroupKind,
-		To:   HTTPRouteGroupKind,
+		From: ListenerSetGroupKind,
+		To:   ListenerGroupKind,
 		Func: func(child Object) []Object {
-			httpRoute := child.(*HTTPRoute)
-			return lo.FlatMap(httpRoute.Spec.ParentRefs, findListenerFromParentRefFunc(gateways, listeners, httpRoute.Namespace))
+			listener := child.(*Listener)
+			if listener.ListenerSet == nil {
+				return nil
+			}
+			return []Object{listener.ListenerSet}
 		},
 	}
 }
 
-// LinkListenerToGRPCRouteFunc returns a link function that teaches a topology how to link GRPCRoutes from known
-// Gateways and gateway Listeners, based on the GRPCRoute's `parentRefs` field.
-// The function links a specific Listener of a Gateway to the GRPCRoute when the `sectionName` field of the parent
-// reference is present, otherwise all Listeners of the parent Gateway are linked to the GRPCRoute.
-func LinkListenerToGRPCRouteFunc(gateways []*Gateway, listeners []*Listener) LinkFunc {
+// LinkListenerSetToHTTPRouteFunc returns a link function that teaches a topology how to link HTTPRoutes from known
+// ListenerSets, based on the HTTPRoute's `parentRefs` field.
+func LinkListenerSetToHTTPRouteFunc(listenerSets []*ListenerSet) LinkFunc {
+	return linkListenerSetToRouteFunc(listenerSets, HTTPRouteGroupKind)
+}
+
+// LinkListenerSetToGRPCRouteFunc returns a link function that teaches a topology how to link GRPCRoutes from known
+// ListenerSets, based on the GRPCRoute's `parentRefs` field.
+func LinkListenerSetToGRPCRouteFunc(listenerSets []*ListenerSet) LinkFunc {
+	return linkListenerSetToRouteFunc(listenerSets, GRPCRouteGroupKind)
+}
+
+// LinkListenerSetToTCPRouteFunc returns a link function that teaches a topology how to link TCPRoutes from known
+// ListenerSets, based on the TCPRoute's `parentRefs` field.
+func LinkListenerSetToTCPRouteFunc(listenerSets []*ListenerSet) LinkFunc {
+	return linkListenerSetToRouteFunc(listenerSets, TCPRouteGroupKind)
+}
+
+// LinkListenerSetToTLSRouteFunc returns a link function that teaches a topology how to link TLSRoutes from known
+// ListenerSets, based on the TLSRoute's `parentRefs` field.
+func LinkListenerSetToTLSRouteFunc(listenerSets []*ListenerSet) LinkFunc {
+	return linkListenerSetToRouteFunc(listenerSets, TLSRouteGroupKind)
+}
+
+// LinkListenerSetToUDPRouteFunc returns a link function that teaches a topology how to link UDPRoutes from known
+// ListenerSets, based on the UDPRoute's `parentRefs` field.
+func LinkListenerSetToUDPRouteFunc(listenerSets []*ListenerSet) LinkFunc {
+	return linkListenerSetToRouteFunc(listenerSets, UDPRouteGroupKind)
+}
+
+// linkListenerSetToRouteFunc links a route from the ListenerSets it refers to in its `parentRefs` field, provided the
+// ListenerSet has a Listener that matches the `sectionName` and `port` fields of the parent reference, when present
+func linkListenerSetToRouteFunc(listenerSets []*ListenerSet, to schema.GroupKind) LinkFunc {
+	return IndexedLinkFunc(ListenerSetGroupKind, to, listenerSets, listenerSetKeys, func(child Object) []string {
+		return lo.FilterMap(parentRefsFromRoute(child), listenerKeyFromParentRefFunc(child.GetNamespace()))
+	})
+}
+
+// listenerSetKeys returns the index keys of a ListenerSet, i.e. the key of the ListenerSet and the keys of each of its
+// Listeners
+func listenerSetKeys(listenerSet *ListenerSet) []string {
+	return append([]string{listenerSetKey(listenerSet)}, lo.FlatMap(ListenersFromListenerSetFunc(listenerSet, 0), func(listener *Listener, _ int) []string {
+		return listenerKeys(listener)[1:]
+	})...)
+}
+
+func listenerSetKey(listenerSet *ListenerSet) string {
+	return listenerSetKeyFromName(listenerSet.Namespace, listenerSet.Name)
+}
+
+// listenerSetKeyFromName returns the index key of a ListenerSet, which is prefixed with the kind so it never collides
+// with the key of a Gateway of the same name
+func listenerSetKeyFromName(namespace, name string) string {
+	return fmt.Sprintf("%s:%s", ListenerSetGroupKind.Kind, namespacedName(namespace, name))
+}
+
+// listenerSetKeyFromParentRef returns the index key of the ListenerSet a parent reference points to, regardless of
+// section name and port
+func listenerSetKeyFromParentRef(parentRef gwapiv1.ParentReference, routeNamespace string) (string, bool) {
+	parentRefGroup := ptr.Deref(parentRef.Group, gwapiv1.GroupName)
+	parentRefKind := ptr.Deref(parentRef.Kind, "Gateway")
+	if parentRefGroup != gwapiv1.GroupName || parentRefKind != gwapiv1.Kind(ListenerSetGroupKind.Kind) {
+		return "", false
+	}
+	listenerSetNamespace := string(ptr.Deref(parentRef.Namespace, gwapiv1.Namespace(routeNamespace)))
+	return listenerSetKeyFromName(listenerSetNamespace, string(parentRef.Name)), true
+}
+
+// gatewayKeyFromListenerSet returns the index key of the parent Gateway of a ListenerSet
+func gatewayKeyFromListenerSet(listenerSet *ListenerSet) string {
+	parentRef := listenerSet.Spec.ParentRef
+	if ptr.Deref(parentRef.Group, gwapiv1.GroupName) != gwapiv1.GroupName || ptr.Deref(parentRef.Kind, "Gateway") != "Gateway" {
+		return ""
+	}
+	gatewayNamespace := string(ptr.Deref(parentRef.Namespace, gwapiv1.Namespace(listenerSet.Namespace)))
+	return namespacedName(gatewayNamespace, string(parentRef.Name))
+}
+
+// LinkListenerToHostnameFunc returns a link function that teaches a topology how to link hostnames from the gateway
+// Listeners that serve them.
+func LinkListenerToHostnameFunc() LinkFunc {
 	return LinkFunc{
 		From: ListenerGroupKind,
-		To:   GRPCRouteGroupKind,
+		To:   HostnameGroupKind,
 		Func: func(child Object) []Object {
-			grpcRoute := child.(*GRPCRoute)
-			return lo.FlatMap(grpcRoute.Spec.ParentRefs, findListenerFromParentRefFunc(gateways, listeners, grpcRoute.Namespace))
+			hostname := child.(*Hostname)
+			return []Object{hostname.Listener}
 		},
 	}
 }
 
+// expandHostnames returns the hostnames served by the gateway Listeners, i.e. the hostnames of the Listeners and the
+// hostnames shared by the Listeners and the routes linked from them by the given links, along with the links from the
+// hostnames to the routes, and a copy of the given links from the Listeners to the routes that do not go through any
+// hostname
+func expandHostnames(listeners []*Listener, listenerToRouteLinks []LinkFunc, routes map[schema.GroupKind][]Object) ([]*Hostname, []LinkFunc, []LinkFunc) {
+	var hostnames []*Hostname
+	hostnamesByKey := make(map[string]*Hostname)
+	hostnameFor := func(listener *Listener, name gwapiv1.Hostname) *Hostname {
+		key := namespacedSectionName(listener.GetLocator(), gwapiv1.SectionName(name))
+		hostname, ok := hostnamesByKey[key]
+		if !ok {
+			hostname = &Hostname{Hostname: name, Listener: listener}
+			hostnamesByKey[key] = hostname
+			hostnames = append(hostnames, hostname)
+		}
+		return hostname
+	}
+
+	for _, listener := range listeners {
+		if listener.Hostname != nil && *listener.Hostname != "" {
+			hostnameFor(listener, *listener.Hostname)
+		}
+	}
+
+	hostnamesByRoute := make(map[string][]Object)
+	throughHostname := make(map[string]struct{})
+	for _, link := range listenerToRouteLinks {
+		for _, route := range routes[link.To] {
+			for _, parent := range link.Func(route) {
+				listener, ok := parent.(*Listener)
+				if !ok {
+					continue
+				}
+				effectiveHostnames, _ := EffectiveHostnames(listener, route)
+				for _, name := range effectiveHostnames {
+					hostname := hostnameFor(listener, name)
+					if !lo.Contains(hostnamesByRoute[route.GetLocator()], Object(hostname)) {
+						hostnamesByRoute[route.GetLocator()] = append(hostnamesByRoute[route.GetLocator()], hostname)
+					}
+				}
+				if len(effectiveHostnames) > 0 {
+					throughHostname[listener.GetLocator()+route.GetLocator()] = struct{}{}
+				}
+			}
+		}
+	}
+
+	hostnameToRouteLinks := lo.Map(listenerToRouteLinks, func(link LinkFunc, _ int) LinkFunc {
+		return LinkFunc{
+			From: HostnameGroupKind,
+			To:   link.To,
+			Func: func(child Object) []Object {
+				return hostnamesByRoute[child.GetLocator()]
+			},
+		}
+	})
+	listenerToRouteLinks = lo.Map(listenerToRouteLinks, func(link LinkFunc, _ int) LinkFunc {
+		return filterLinkFunc(link, func(parent, child Object) bool {
+			_, ok := throughHostname[parent.GetLocator()+child.GetLocator()]
+			return !ok
+		})
+	})
+	return hostnames, hostnameToRouteLinks, listenerToRouteLinks
+}
+
+// LinkListenerToHTTPRouteFunc returns a link function that teaches a topology how to link HTTPRoutes from known
+// Gateways and gateway Listeners, based on the HTTPRoute's `parentRefs` field.
+// The function links the Listeners of a Gateway that match the `sectionName` and `port` fields of the parent reference
+// to the HTTPRoute, when these fields are present, otherwise all Listeners of the parent Gateway are linked to the
+// HTTPRoute.
+func LinkListenerToHTTPRouteFunc(gateways []*Gateway, listeners []*Listener) LinkFunc {
+	return IndexedLinkFunc(ListenerGroupKind, HTTPRouteGroupKind, listeners, listenerKeysFunc(gateways), func(child Object) []string {
+		httpRoute := child.(*HTTPRoute)
+		return lo.FilterMap(httpRoute.Spec.ParentRefs, listenerKeyFromParentRefFunc(httpRoute.Namespace))
+	})
+}
+
+// LinkListenerToGRPCRouteFunc returns a link function that teaches a topology how to link GRPCRoutes from known
+// Gateways and gateway Listeners, based on the GRPCRoute's `parentRefs` field.
+// The function links the Listeners of a Gateway that match the `sectionName` and `port` fields of the parent reference
+// to the GRPCRoute, when these fields are present, otherwise all Listeners of the parent Gateway are linked to the
+// GRPCRoute.
+func LinkListenerToGRPCRouteFunc(gateways []*Gateway, listeners []*Listener) LinkFunc {
+	return IndexedLinkFunc(ListenerGroupKind, GRPCRouteGroupKind, listeners, listenerKeysFunc(gateways), func(child Object) []string {
+		grpcRoute := child.(*GRPCRoute)
+		return lo.FilterMap(grpcRoute.Spec.ParentRefs, listenerKeyFromParentRefFunc(grpcRoute.Namespace))
+	})
+}
+
 // LinkListenerToTCPRouteFunc returns a link function that teaches a topology how to link TCPRoutes from known
 // Gateways and gateway Listeners, based on the TCPRoute's `parentRefs` field.
-// The function links a specific Listener of a Gateway to the TCPRoute when the `sectionName` field of the parent
-// reference is present, otherwise all Listeners of the parent Gateway are linked to the TCPRoute.
+// The function links the Listeners of a Gateway that match the `sectionName` and `port` fields of the parent reference
+// to the TCPRoute, when these fields are present, otherwise all Listeners of the parent Gateway are linked to the
+// TCPRoute.
 func LinkListenerToTCPRouteFunc(gateways []*Gateway, listeners []*Listener) LinkFunc {
-	return LinkFunc{
-		From: ListenerGroupKind,
-		To:   TCPRouteGroupKind,
-		Func: func(child Object) []Object {
-			tcpRoute := child.(*TCPRoute)
-			return lo.FlatMap(tcpRoute.Spec.ParentRefs, findListenerFromParentRefFunc(gateways, listeners, tcpRoute.Namespace))
-		},
-	}
+	return IndexedLinkFunc(ListenerGroupKind, TCPRouteGroupKind, listeners, listenerKeysFunc(gateways), func(child Object) []string {
+		tcpRoute := child.(*TCPRoute)
+		return lo.FilterMap(tcpRoute.Spec.ParentRefs, listenerKeyFromParentRefFunc(tcpRoute.Namespace))
+	})
 }
 
 // LinkListenerToTLSRouteFunc returns a link function that teaches a topology how to link TLSRoutes from known
 // Gateways and gateway Listeners, based on the TLSRoute's `parentRefs` field.
-// The function links a specific Listener of a Gateway to the TLSRoute when the `sectionName` field of the parent
-// reference is present, otherwise all Listeners of the parent Gateway are linked to the TLSRoute.
+// The function links the Listeners of a Gateway that match the `sectionName` and `port` fields of the parent reference
+// to the TLSRoute, when these fields are present, otherwise all Listeners of the parent Gateway are linked to the
+// TLSRoute.
 func LinkListenerToTLSRouteFunc(gateways []*Gateway, listeners []*Listener) LinkFunc {
-	return LinkFunc{
-		From: ListenerGroupKind,
-		To:   TLSRouteGroupKind,
-		Func: func(child Object) []Object {
-			tlsRoute := child.(*TLSRoute)
-			return lo.FlatMap(tlsRoute.Spec.ParentRefs, findListenerFromParentRefFunc(gateways, listeners, tlsRoute.Namespace))
-		},
-	}
+	return IndexedLinkFunc(ListenerGroupKind, TLSRouteGroupKind, listeners, listenerKeysFunc(gateways), func(child Object) []string {
+		tlsRoute := child.(*TLSRoute)
+		return lo.FilterMap(tlsRoute.Spec.ParentRefs, listenerKeyFromParentRefFunc(tlsRoute.Namespace))
+	})
 }
 
 // LinkListenerToUDPRouteFunc returns a link function that teaches a topology how to link UDPRoutes from known
 // Gateways and gateway Listeners, based on the UDPRoute's `parentRefs` field.
-// The function links a specific Listener of a Gateway to the UDPRoute when the `sectionName` field of the parent
-// reference is present, otherwise all Listeners of the parent Gateway are linked to the UDPRoute.
+// The function links the Listeners of a Gateway that match the `sectionName` and `port` fields of the parent reference
+// to the UDPRoute, when these fields are present, otherwise all Listeners of the parent Gateway are linked to the
+// UDPRoute.
 func LinkListenerToUDPRouteFunc(gateways []*Gateway, listeners []*Listener) LinkFunc {
-	return LinkFunc{
-		From: ListenerGroupKind,
-		To:   UDPRouteGroupKind,
-		Func: func(child Object) []Object {
-			udpRoute := child.(*UDPRoute)
-			return lo.FlatMap(udpRoute.Spec.ParentRefs, findListenerFromParentRefFunc(gateways, listeners, udpRoute.Namespace))
-		},
+	return IndexedLinkFunc(ListenerGroupKind, UDPRouteGroupKind, listeners, listenerKeysFunc(gateways), func(child Object) []string {
+		udpRoute := child.(*UDPRoute)
+		return lo.FilterMap(udpRoute.Spec.ParentRefs, listenerKeyFromParentRefFunc(udpRoute.Namespace))
+	})
+}
+
+// ListenerAllowsRouteFunc returns a function that tells whether a gateway Listener allows a route to attach to it,
+// according to the `allowedRoutes` field and the protocol of the Listener, as defined by Gateway API.
+// The labels of the namespaces are read from the given Namespaces, for the Listeners that select the namespaces of the
+// routes by labels. Routes in unknown namespaces are not allowed by such Listeners.
+func ListenerAllowsRouteFunc(namespaces []*Namespace) func(listener *Listener, route Object) bool {
+	namespaceLabels := make(map[string]map[string]string, len(namespaces))
+	for _, namespace := range namespaces {
+		namespaceLabels[namespace.Name] = namespace.Labels
+	}
+	return func(listener *Listener, route Object) bool {
+		return listenerAllowsRouteNamespace(listener, route.GetNamespace(), namespaceLabels) && listenerAllowsRouteKind(listener, route.GroupVersionKind().GroupKind())
 	}
 }
 
-// findListenerFromParentRefFunc is a common function to find a gateway Listener from a xRoute's `parentRef` field
-func findListenerFromParentRefFunc(gateways []*Gateway, listeners []*Listener, routeNamespace string) func(parentRef gwapiv1.ParentReference, _ int) []Object {
-	return func(parentRef gwapiv1.ParentReference, _ int) []Object {
-		parentRefGroup := ptr.Deref(parentRef.Group, gwapiv1.GroupName)
-		parentRefKind := ptr.Deref(parentRef.Kind, "Gateway")
-		if parentRefGroup != gwapiv1.GroupName || parentRefKind != "Gateway" {
-			return nil
+func listenerAllowsRouteNamespace(listener *Listener, routeNamespace string, namespaceLabels map[string]map[string]string) bool {
+	from := gwapiv1.NamespacesFromSame
+	var selector *metav1.LabelSelector
+	if listener.AllowedRoutes != nil && listener.AllowedRoutes.Namespaces != nil {
+		from = ptr.Deref(listener.AllowedRoutes.Namespaces.From, gwapiv1.NamespacesFromSame)
+		selector = listener.AllowedRoutes.Namespaces.Selector
+	}
+	switch from {
+	case gwapiv1.NamespacesFromAll:
+		return true
+	case gwapiv1.NamespacesFromSelector:
+		labels, ok := namespaceLabels[routeNamespace]
+		if !ok || selector == nil {
+			return false
+		}
+		s, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return false
+		}
+		return s.Matches(k8slabels.Set(labels))
+	default:
+		return routeNamespace == listener.GetNamespace()
+	}
+}
+
+// routeKindsByProtocol are the kinds of routes compatible with each of the protocols defined by Gateway API
+var routeKindsByProtocol = map[gwapiv1.ProtocolType][]schema.GroupKind{
+	gwapiv1.HTTPProtocolType:  {HTTPRouteGroupKind, GRPCRouteGroupKind},
+	gwapiv1.HTTPSProtocolType: {HTTPRouteGroupKind, GRPCRouteGroupKind},
+	gwapiv1.TLSProtocolType:   {TLSRouteGroupKind, TCPRouteGroupKind},
+	gwapiv1.TCPProtocolType:   {TCPRouteGroupKind},
+	gwapiv1.UDPProtocolType:   {UDPRouteGroupKind},
+}
+
+func listenerAllowsRouteKind(listener *Listener, routeKind schema.GroupKind) bool {
+	// implementation-specific protocols are assumed to be compatible with any kind of route
+	if compatibleKinds, ok := routeKindsByProtocol[listener.Protocol]; ok && !lo.Contains(compatibleKinds, routeKind) {
+		return false
+	}
+	if listener.AllowedRoutes == nil || len(listener.AllowedRoutes.Kinds) == 0 {
+		return true
+	}
+	return lo.ContainsBy(listener.AllowedRoutes.Kinds, func(kind gwapiv1.RouteGroupKind) bool {
+		return string(ptr.Deref(kind.Group, gwapiv1.GroupName)) == routeKind.Group && string(kind.Kind) == routeKind.Kind
+	})
+}
+
+// routeParentStatusAcceptsFunc returns a function that tells whether the status of a route reports a parent reference
+// of the route that points to a gateway Listener as accepted by any of the controller names, or by the controller of
+// the GatewayClass of the Gateway if no controller names are given
+func routeParentStatusAcceptsFunc(gatewayClasses []*GatewayClass, controllerNames []gwapiv1.GatewayController) func(listener *Listener, route Object) bool {
+	gatewayClassControllers := lo.SliceToMap(gatewayClasses, func(gatewayClass *GatewayClass) (string, gwapiv1.GatewayController) {
+		return gatewayClass.Name, gatewayClass.Spec.ControllerName
+	})
+	return func(listener *Listener, route Object) bool {
+		controllers := controllerNames
+		if len(controllers) == 0 {
+			if listener.Gateway == nil {
+				return false
+			}
+			if controller, ok := gatewayClassControllers[string(listener.Gateway.Spec.GatewayClassName)]; ok {
+				controllers = []gwapiv1.GatewayController{controller}
+			}
 		}
-		gatewayNamespace := string(ptr.Deref(parentRef.Namespace, gwapiv1.Namespace(routeNamespace)))
-		gateway, ok := lo.Find(gateways, func(g *Gateway) bool {
-			return g.Namespace == gatewayNamespace && g.Name == string(parentRef.Name)
+		keys := listenerKeys(listener)
+		keyFromParentRef := listenerKeyFromParentRefFunc(route.GetNamespace())
+		parentRefKeys := lo.FilterMap(parentRefsFromRoute(route), keyFromParentRef)
+		return lo.ContainsBy(routeParentStatuses(route), func(status gwapiv1.RouteParentStatus) bool {
+			if !lo.Contains(controllers, status.ControllerName) || !meta.IsStatusConditionTrue(status.Conditions, string(gwapiv1.RouteConditionAccepted)) {
+				return false
+			}
+			// the status must be of a parent reference still present in the spec of the route
+			key, ok := keyFromParentRef(status.ParentRef, 0)
+			return ok && lo.Contains(parentRefKeys, key) && lo.Contains(keys, key)
 		})
+	}
+}
+
+// backendRefsFromRoute returns the backendRefs of all rules of a route of any of the kinds defined by Gateway API
+func backendRefsFromRoute(route Object) []gwapiv1.BackendRef {
+	switch r := route.(type) {
+	case *HTTPRoute:
+		return lo.FlatMap(r.Spec.Rules, func(rule gwapiv1.HTTPRouteRule, _ int) []gwapiv1.BackendRef {
+			return lo.Map(rule.BackendRefs, backendRefFromHTTPBackendRef)
+		})
+	case *GRPCRoute:
+		return lo.FlatMap(r.Spec.Rules, func(rule gwapiv1.GRPCRouteRule, _ int) []gwapiv1.BackendRef {
+			return lo.Map(rule.BackendRefs, backendRefFromGRPCBackendRef)
+		})
+	case *TCPRoute:
+		return lo.FlatMap(r.Spec.Rules, func(rule gwapiv1.TCPRouteRule, _ int) []gwapiv1.BackendRef { return rule.BackendRefs })
+	case *TLSRoute:
+		return lo.FlatMap(r.Spec.Rules, func(rule gwapiv1.TLSRouteRule, _ int) []gwapiv1.BackendRef { return rule.BackendRefs })
+	case *UDPRoute:
+		return lo.FlatMap(r.Spec.Rules, func(rule gwapiv1.UDPRouteRule, _ int) []gwapiv1.BackendRef { return rule.BackendRefs })
+	}
+	return nil
+}
+
+// routeParentStatuses returns the status of the parents of a route of any of the kinds defined by Gateway API
+func routeParentStatuses(route Object) []gwapiv1.RouteParentStatus {
+	switch r := route.(type) {
+	case *HTTPRoute:
+		return r.Status.Parents
+	case *GRPCRoute:
+		return r.Status.Parents
+	case *TCPRoute:
+		return r.Status.Parents
+	case *TLSRoute:
+		return r.Status.Parents
+	case *UDPRoute:
+		return r.Status.Parents
+	}
+	return nil
+}
+
+// EffectiveHostnames returns the hostnames shared by a gateway Listener and a route, i.e. the hostnames the route
+// serves through the Listener, and whether the hostnames of the Listener and the route intersect at all.
+// An empty list of hostnames when they intersect means any hostname.
+func EffectiveHostnames(listener *Listener, route Object) ([]gwapiv1.Hostname, bool) {
+	return IntersectHostnames(listener.Hostname, hostnamesFromRoute(route))
+}
+
+// IntersectHostnames returns the intersection between the hostname of a gateway Listener and the hostnames of a
+// route, as defined by Gateway API, and whether the intersection is not empty.
+// A Listener without hostname matches all hostnames of the route; a route without hostnames matches the hostname of
+// the Listener. Wildcard hostnames of the route that are broader than the hostname of the Listener are narrowed down
+// to the hostname of the Listener. An empty list of hostnames when they intersect means any hostname.
+func IntersectHostnames(listenerHostname *gwapiv1.Hostname, routeHostnames []gwapiv1.Hostname) ([]gwapiv1.Hostname, bool) {
+	if listenerHostname == nil || *listenerHostname == "" {
+		return routeHostnames, true
+	}
+	if len(routeHostnames) == 0 {
+		return []gwapiv1.Hostname{*listenerHostname}, true
+	}
+	hostnames := lo.Uniq(lo.FilterMap(routeHostnames, func(hostname gwapiv1.Hostname, _ int) (gwapiv1.Hostname, bool) {
+		switch {
+		case hostnameMatches(hostname, *listenerHostname):
+			return hostname, true
+		case hostnameMatches(*listenerHostname, hostname):
+			return *listenerHostname, true
+		}
+		return "", false
+	}))
+	return hostnames, len(hostnames) > 0
+}
+
+// hostnameMatches tells whether a hostname is equal to or a subset of another, possibly wildcard, hostname
+func hostnameMatches(hostname, superset gwapiv1.Hostname) bool {
+	if !strings.HasPrefix(string(superset), "*") {
+		return hostname == superset
+	}
+	suffix := strings.TrimPrefix(string(superset), "*")
+	if strings.HasPrefix(string(hostname), "*") {
+		return strings.HasSuffix(strings.TrimPrefix(string(hostname), "*"), suffix)
+	}
+	return strings.HasSuffix(string(hostname), suffix)
+}
+
+// hostnamesFromRoute returns the hostnames of a route of any of the kinds defined by Gateway API that have hostnames
+func hostnamesFromRoute(route Object) []gwapiv1.Hostname {
+	switch r := route.(type) {
+	case *HTTPRoute:
+		return r.Spec.Hostnames
+	case *GRPCRoute:
+		return r.Spec.Hostnames
+	case *TLSRoute:
+		return r.Spec.Hostnames
+	}
+	return nil
+}
+
+// routeRefersToListener tells whether any of the parentRefs of a route points to the gateway Listener, either by
+// section name, by port, or by referring to the whole Gateway
+func routeRefersToListener(route Object, listener *Listener) bool {
+	keys := listenerKeys(listener)
+	return lo.ContainsBy(lo.FilterMap(parentRefsFromRoute(route), listenerKeyFromParentRefFunc(route.GetNamespace())), func(key string) bool {
+		return lo.Contains(keys, key)
+	})
+}
+
+// parentRefsFromRoute returns the parentRefs of a route of any of the kinds defined by Gateway API
+func parentRefsFromRoute(route Object) []gwapiv1.ParentReference {
+	switch r := route.(type) {
+	case *HTTPRoute:
+		return r.Spec.ParentRefs
+	case *GRPCRoute:
+		return r.Spec.ParentRefs
+	case *TCPRoute:
+		return r.Spec.ParentRefs
+	case *TLSRoute:
+		return r.Spec.ParentRefs
+	case *UDPRoute:
+		return r.Spec.ParentRefs
+	}
+	return nil
+}
+
+// listenerKeyFromParentRefFunc is a common function to get the index key of gateway Listeners from a xRoute's
+// `parentRef` field.
+// The key points to the Listeners of the Gateway that match the `sectionName` and `port` fields of the parent
+// reference, when present, otherwise to all Listeners of the Gateway.
+func listenerKeyFromParentRefFunc(routeNamespace string) func(parentRef gwapiv1.ParentReference, _ int) (string, bool) {
+	return func(parentRef gwapiv1.ParentReference, _ int) (string, bool) {
+		key, ok := parentKeyFromParentRef(parentRef, routeNamespace)
 		if !ok {
-			return nil
+			return "", false
 		}
 		if parentRef.SectionName != nil {
-			listener, ok := lo.Find(listeners, func(l *Listener) bool {
-				return l.Gateway.GetLocator() == gateway.GetLocator() && l.Name == *parentRef.SectionName
-			})
-			if !ok {
-				return nil
-			}
-			return []Object{listener}
+			key = namespacedSectionName(key, *parentRef.SectionName)
 		}
-		return lo.FilterMap(listeners, func(l *Listener, _ int) (Object, bool) {
-			return l, l.Gateway.GetLocator() == gateway.GetLocator()
-		})
+		if parentRef.Port != nil {
+			key = portKey(key, int32(*parentRef.Port))
+		}
+		return key, true
+	}
+}
+
+// listenerKeysFunc returns a function that returns the index keys of a gateway Listener, provided the Listener
+// belongs to one of the given Gateways
+func listenerKeysFunc(gateways []*Gateway) func(listener *Listener) []string {
+	knownGateways := lo.SliceToMap(gateways, func(gateway *Gateway) (string, struct{}) {
+		return gatewayKey(gateway), struct{}{}
+	})
+	return func(listener *Listener) []string {
+		if listener.ListenerSet != nil {
+			return listenerKeys(listener)
+		}
+		if listener.Gateway == nil {
+			return nil
+		}
+		if _, ok := knownGateways[gatewayKey(listener.Gateway)]; !ok {
+			return nil
+		}
+		return listenerKeys(listener)
+	}
+}
+
+// listenerKeys returns the index keys of a gateway Listener, i.e. the keys of the Gateway or ListenerSet that defines
+// the Listener and of the Listener, both with and without the port of the Listener
+func listenerKeys(listener *Listener) []string {
+	var key string
+	switch {
+	case listener.ListenerSet != nil:
+		key = listenerSetKey(listener.ListenerSet)
+	case listener.Gateway != nil:
+		key = gatewayKey(listener.Gateway)
+	default:
+		return nil
 	}
+	sectionKey := namespacedSectionName(key, listener.Name)
+	port := int32(listener.Port)
+	return []string{key, sectionKey, portKey(key, port), portKey(sectionKey, port)}
 }
 
 // LinkHTTPRouteToHTTPRouteRuleFunc returns a link function that teaches a topology how to link HTTPRouteRules from the
@@ -711,126 +1816,110 @@ func LinkHTTPRouteToHTTPRouteRuleFunc() LinkFunc {
 	}
 }
 
+// LinkHTTPRouteRuleToHTTPRouteMatchFunc returns a link function that teaches a topology how to link HTTPRouteMatches
+// from the HTTPRouteRule they are strongly related to.
+func LinkHTTPRouteRuleToHTTPRouteMatchFunc() LinkFunc {
+	return LinkFunc{
+		From: HTTPRouteRuleGroupKind,
+		To:   HTTPRouteMatchGroupKind,
+		Func: func(child Object) []Object {
+			httpRouteMatch := child.(*HTTPRouteMatch)
+			return []Object{httpRouteMatch.HTTPRouteRule}
+		},
+	}
+}
+
+// LinkGRPCRouteRuleToGRPCRouteMatchFunc returns a link function that teaches a topology how to link GRPCRouteMatches
+// from the GRPCRouteRule they are strongly related to.
+func LinkGRPCRouteRuleToGRPCRouteMatchFunc() LinkFunc {
+	return LinkFunc{
+		From: GRPCRouteRuleGroupKind,
+		To:   GRPCRouteMatchGroupKind,
+		Func: func(child Object) []Object {
+			grpcRouteMatch := child.(*GRPCRouteMatch)
+			return []Object{grpcRouteMatch.GRPCRouteRule}
+		},
+	}
+}
+
 // LinkHTTPRouteToServiceFunc returns a link function that teaches a topology how to link Services from known
 // HTTPRoutes, based on the HTTPRoute's `backendRefs` fields.
 // Set the `strict` parameter to `true` to link only to services that have no port specified in the backendRefs.
 func LinkHTTPRouteToServiceFunc(httpRoutes []*HTTPRoute, strict bool) LinkFunc {
-	return LinkFunc{
-		From: HTTPRouteGroupKind,
-		To:   ServiceGroupKind,
-		Func: func(child Object) []Object {
-			service := child.(*Service)
-			return lo.FilterMap(httpRoutes, func(httpRoute *HTTPRoute, _ int) (Object, bool) {
-				return httpRoute, lo.ContainsBy(httpRoute.Spec.Rules, func(rule gwapiv1.HTTPRouteRule) bool {
-					backendRefs := lo.FilterMap(rule.BackendRefs, func(backendRef gwapiv1.HTTPBackendRef, _ int) (gwapiv1.BackendRef, bool) {
-						return backendRef.BackendRef, !strict || backendRef.Port == nil
-					})
-					return lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(service, httpRoute.Namespace))
-				})
+	return IndexedLinkFunc(HTTPRouteGroupKind, ServiceGroupKind, httpRoutes,
+		func(httpRoute *HTTPRoute) []string {
+			return lo.FlatMap(httpRoute.Spec.Rules, func(rule gwapiv1.HTTPRouteRule, _ int) []string {
+				return lo.FilterMap(lo.Map(rule.BackendRefs, backendRefFromHTTPBackendRef), serviceKeyFromBackendRefFunc(httpRoute.Namespace, strict, httpRoute.RefusedBackendRefs))
 			})
 		},
-	}
+		serviceKeys,
+	)
 }
 
 // LinkHTTPRouteToServicePortFunc returns a link function that teaches a topology how to link services ports from known
 // HTTPRoutes, based on the HTTPRoute's `backendRefs` fields.
 // The link function disregards backend references that do not specify a port number.
 func LinkHTTPRouteToServicePortFunc(httpRoutes []*HTTPRoute) LinkFunc {
-	return LinkFunc{
-		From: HTTPRouteGroupKind,
-		To:   ServicePortGroupKind,
-		Func: func(child Object) []Object {
-			servicePort := child.(*ServicePort)
-			return lo.FilterMap(httpRoutes, func(httpRoute *HTTPRoute, _ int) (Object, bool) {
-				return httpRoute, lo.ContainsBy(httpRoute.Spec.Rules, func(rule gwapiv1.HTTPRouteRule) bool {
-					backendRefs := lo.FilterMap(rule.BackendRefs, func(backendRef gwapiv1.HTTPBackendRef, _ int) (gwapiv1.BackendRef, bool) {
-						return backendRef.BackendRef, backendRef.Port != nil && int32(*backendRef.Port) == servicePort.Port
-					})
-					return lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(servicePort.Service, httpRoute.Namespace))
-				})
+	return IndexedLinkFunc(HTTPRouteGroupKind, ServicePortGroupKind, httpRoutes,
+		func(httpRoute *HTTPRoute) []string {
+			return lo.FlatMap(httpRoute.Spec.Rules, func(rule gwapiv1.HTTPRouteRule, _ int) []string {
+				return lo.FilterMap(lo.Map(rule.BackendRefs, backendRefFromHTTPBackendRef), servicePortKeyFromBackendRefFunc(httpRoute.Namespace, httpRoute.RefusedBackendRefs))
 			})
 		},
-	}
+		servicePortKeys,
+	)
 }
 
 // LinkHTTPRouteRuleToServiceFunc returns a link function that teaches a topology how to link Services from known
 // HTTPRouteRules, based on the HTTPRouteRule's `backendRefs` field.
 // Set the `strict` parameter to `true` to link only to services that have no port specified in the backendRefs.
 func LinkHTTPRouteRuleToServiceFunc(httpRouteRules []*HTTPRouteRule, strict bool) LinkFunc {
-	return LinkFunc{
-		From: HTTPRouteRuleGroupKind,
-		To:   ServiceGroupKind,
-		Func: func(child Object) []Object {
-			service := child.(*Service)
-			return lo.FilterMap(httpRouteRules, func(httpRouteRule *HTTPRouteRule, _ int) (Object, bool) {
-				backendRefs := lo.FilterMap(httpRouteRule.BackendRefs, func(backendRef gwapiv1.HTTPBackendRef, _ int) (gwapiv1.BackendRef, bool) {
-					return backendRef.BackendRef, !strict || backendRef.Port == nil
-				})
-				return httpRouteRule, lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(service, httpRouteRule.HTTPRoute.Namespace))
-			})
+	return IndexedLinkFunc(HTTPRouteRuleGroupKind, ServiceGroupKind, httpRouteRules,
+		func(httpRouteRule *HTTPRouteRule) []string {
+			return lo.FilterMap(lo.Map(httpRouteRule.BackendRefs, backendRefFromHTTPBackendRef), serviceKeyFromBackendRefFunc(httpRouteRule.HTTPRoute.Namespace, strict, httpRouteRule.HTTPRoute.RefusedBackendRefs))
 		},
-	}
+		serviceKeys,
+	)
 }
 
 // LinkHTTPRouteRuleToServicePortFunc returns a link function that teaches a topology how to link services ports from
 // known HTTPRouteRules, based on the HTTPRouteRule's `backendRefs` field.
 // The link function disregards backend references that do not specify a port number.
 func LinkHTTPRouteRuleToServicePortFunc(httpRouteRules []*HTTPRouteRule) LinkFunc {
-	return LinkFunc{
-		From: HTTPRouteRuleGroupKind,
-		To:   ServicePortGroupKind,
-		Func: func(child Object) []Object {
-			servicePort := child.(*ServicePort)
-			return lo.FilterMap(httpRouteRules, func(httpRouteRule *HTTPRouteRule, _ int) (Object, bool) {
-				backendRefs := lo.FilterMap(httpRouteRule.BackendRefs, func(backendRef gwapiv1.HTTPBackendRef, _ int) (gwapiv1.BackendRef, bool) {
-					return backendRef.BackendRef, backendRef.Port != nil && int32(*backendRef.Port) == servicePort.Port
-				})
-				return httpRouteRule, lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(servicePort.Service, httpRouteRule.HTTPRoute.Namespace))
-			})
+	return IndexedLinkFunc(HTTPRouteRuleGroupKind, ServicePortGroupKind, httpRouteRules,
+		func(httpRouteRule *HTTPRouteRule) []string {
+			return lo.FilterMap(lo.Map(httpRouteRule.BackendRefs, backendRefFromHTTPBackendRef), servicePortKeyFromBackendRefFunc(httpRouteRule.HTTPRoute.Namespace, httpRouteRule.HTTPRoute.RefusedBackendRefs))
 		},
-	}
+		servicePortKeys,
+	)
 }
 
 // LinkGRPCRouteToServiceFunc returns a link function that teaches a topology how to link Services from known
 // GRPCRoutes, based on the GRPCRoute's `backendRefs` fields.
 // Set the `strict` parameter to `true` to link only to services that have no port specified in the backendRefs.
 func LinkGRPCRouteToServiceFunc(routes []*GRPCRoute, strict bool) LinkFunc {
-	return LinkFunc{
-		From: GRPCRouteGroupKind,
-		To:   ServiceGroupKind,
-		Func: func(child Object) []Object {
-			service := child.(*Service)
-			return lo.FilterMap(routes, func(route *GRPCRoute, _ int) (Object, bool) {
-				return route, lo.ContainsBy(route.Spec.Rules, func(rule gwapiv1.GRPCRouteRule) bool {
-					backendRefs := lo.FilterMap(rule.BackendRefs, func(backendRef gwapiv1.GRPCBackendRef, _ int) (gwapiv1.BackendRef, bool) {
-						return backendRef.BackendRef, !strict || backendRef.Port == nil
-					})
-					return lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(service, route.Namespace))
-				})
+	return IndexedLinkFunc(GRPCRouteGroupKind, ServiceGroupKind, routes,
+		func(route *GRPCRoute) []string {
+			return lo.FlatMap(route.Spec.Rules, func(rule gwapiv1.GRPCRouteRule, _ int) []string {
+				return lo.FilterMap(lo.Map(rule.BackendRefs, backendRefFromGRPCBackendRef), serviceKeyFromBackendRefFunc(route.Namespace, strict, route.RefusedBackendRefs))
 			})
 		},
-	}
+		serviceKeys,
+	)
 }
 
 // LinkGRPCRouteToServicePortFunc returns a link function that teaches a topology how to link services ports from known
 // GRPCRoutes, based on the GRPCRoute's `backendRefs` fields.
 // The link function disregards backend references that do not specify a port number.
 func LinkGRPCRouteToServicePortFunc(routes []*GRPCRoute) LinkFunc {
-	return LinkFunc{
-		From: GRPCRouteGroupKind,
-		To:   ServicePortGroupKind,
-		Func: func(child Object) []Object {
-			servicePort := child.(*ServicePort)
-			return lo.FilterMap(routes, func(route *GRPCRoute, _ int) (Object, bool) {
-				return route, lo.ContainsBy(route.Spec.Rules, func(rule gwapiv1.GRPCRouteRule) bool {
-					backendRefs := lo.FilterMap(rule.BackendRefs, func(backendRef gwapiv1.GRPCBackendRef, _ int) (gwapiv1.BackendRef, bool) {
-						return backendRef.BackendRef, backendRef.Port != nil && int32(*backendRef.Port) == servicePort.Port
-					})
-					return lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(servicePort.Service, route.Namespace))
-				})
+	return IndexedLinkFunc(GRPCRouteGroupKind, ServicePortGroupKind, routes,
+		func(route *GRPCRoute) []string {
+			return lo.FlatMap(route.Spec.Rules, func(rule gwapiv1.GRPCRouteRule, _ int) []string {
+				return lo.FilterMap(lo.Map(rule.BackendRefs, backendRefFromGRPCBackendRef), servicePortKeyFromBackendRefFunc(route.Namespace, route.RefusedBackendRefs))
 			})
 		},
-	}
+		servicePortKeys,
+	)
 }
 
 // LinkGRPCRouteToGRPCRouteRuleFunc returns a link function that teaches a topology how to link GRPCRouteRule from the
@@ -850,80 +1939,52 @@ func LinkGRPCRouteToGRPCRouteRuleFunc() LinkFunc {
 // GRPCRouteRules, based on the GRPCRouteRule's `backendRefs` field.
 // Set the `strict` parameter to `true` to link only to services that have no port specified in the backendRefs.
 func LinkGRPCRouteRuleToServiceFunc(routeRules []*GRPCRouteRule, strict bool) LinkFunc {
-	return LinkFunc{
-		From: GRPCRouteRuleGroupKind,
-		To:   ServiceGroupKind,
-		Func: func(child Object) []Object {
-			service := child.(*Service)
-			return lo.FilterMap(routeRules, func(routeRule *GRPCRouteRule, _ int) (Object, bool) {
-				backendRefs := lo.FilterMap(routeRule.BackendRefs, func(backendRef gwapiv1.GRPCBackendRef, _ int) (gwapiv1.BackendRef, bool) {
-					return backendRef.BackendRef, !strict || backendRef.Port == nil
-				})
-				return routeRule, lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(service, routeRule.GRPCRoute.Namespace))
-			})
+	return IndexedLinkFunc(GRPCRouteRuleGroupKind, ServiceGroupKind, routeRules,
+		func(routeRule *GRPCRouteRule) []string {
+			return lo.FilterMap(lo.Map(routeRule.BackendRefs, backendRefFromGRPCBackendRef), serviceKeyFromBackendRefFunc(routeRule.GRPCRoute.Namespace, strict, routeRule.GRPCRoute.RefusedBackendRefs))
 		},
-	}
+		serviceKeys,
+	)
 }
 
 // LinkGRPCRouteRuleToServicePortFunc returns a link function that teaches a topology how to link services ports from
 // known GRPCRouteRules, based on the GRPCRouteRule's `backendRefs` field.
 // The link function disregards backend references that do not specify a port number.
 func LinkGRPCRouteRuleToServicePortFunc(routeRules []*GRPCRouteRule) LinkFunc {
-	return LinkFunc{
-		From: GRPCRouteRuleGroupKind,
-		To:   ServicePortGroupKind,
-		Func: func(child Object) []Object {
-			servicePort := child.(*ServicePort)
-			return lo.FilterMap(routeRules, func(routeRule *GRPCRouteRule, _ int) (Object, bool) {
-				backendRefs := lo.FilterMap(routeRule.BackendRefs, func(backendRef gwapiv1.GRPCBackendRef, _ int) (gwapiv1.BackendRef, bool) {
-					return backendRef.BackendRef, backendRef.Port != nil && int32(*backendRef.Port) == servicePort.Port
-				})
-				return routeRule, lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(servicePort.Service, routeRule.GRPCRoute.Namespace))
-			})
+	return IndexedLinkFunc(GRPCRouteRuleGroupKind, ServicePortGroupKind, routeRules,
+		func(routeRule *GRPCRouteRule) []string {
+			return lo.FilterMap(lo.Map(routeRule.BackendRefs, backendRefFromGRPCBackendRef), servicePortKeyFromBackendRefFunc(routeRule.GRPCRoute.Namespace, routeRule.GRPCRoute.RefusedBackendRefs))
 		},
-	}
+		servicePortKeys,
+	)
 }
 
 // LinkTCPRouteToServiceFunc returns a link function that teaches a topology how to link Services from known
 // GRPCRoutes, based on the TCPRoute's `backendRefs` fields.
 // Set the `strict` parameter to `true` to link only to services that have no port specified in the backendRefs.
 func LinkTCPRouteToServiceFunc(routes []*TCPRoute, strict bool) LinkFunc {
-	return LinkFunc{
-		From: TCPRouteGroupKind,
-		To:   ServiceGroupKind,
-		Func: func(child Object) []Object {
-			service := child.(*Service)
-			return lo.FilterMap(routes, func(route *TCPRoute, _ int) (Object, bool) {
-				return route, lo.ContainsBy(route.Spec.Rules, func(rule gwapiv1.TCPRouteRule) bool {
-					backendRefs := lo.Filter(rule.BackendRefs, func(backendRef gwapiv1.BackendRef, _ int) bool {
-						return !strict || backendRef.Port == nil
-					})
-					return lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(service, route.Namespace))
-				})
+	return IndexedLinkFunc(TCPRouteGroupKind, ServiceGroupKind, routes,
+		func(route *TCPRoute) []string {
+			return lo.FlatMap(route.Spec.Rules, func(rule gwapiv1.TCPRouteRule, _ int) []string {
+				return lo.FilterMap(rule.BackendRefs, serviceKeyFromBackendRefFunc(route.Namespace, strict, route.RefusedBackendRefs))
 			})
 		},
-	}
+		serviceKeys,
+	)
 }
 
 // LinkTCPRouteToServicePortFunc returns a link function that teaches a topology how to link services ports from known
 // TCPRoutes, based on the TCPRoute's `backendRefs` fields.
 // The link function disregards backend references that do not specify a port number.
 func LinkTCPRouteToServicePortFunc(routes []*TCPRoute) LinkFunc {
-	return LinkFunc{
-		From: TCPRouteGroupKind,
-		To:   ServicePortGroupKind,
-		Func: func(child Object) []Object {
-			servicePort := child.(*ServicePort)
-			return lo.FilterMap(routes, func(route *TCPRoute, _ int) (Object, bool) {
-				return route, lo.ContainsBy(route.Spec.Rules, func(rule gwapiv1.TCPRouteRule) bool {
-					backendRefs := lo.Filter(rule.BackendRefs, func(backendRef gwapiv1.BackendRef, _ int) bool {
-						return backendRef.Port != nil && int32(*backendRef.Port) == servicePort.Port
-					})
-					return lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(servicePort.Service, route.Namespace))
-				})
+	return IndexedLinkFunc(TCPRouteGroupKind, ServicePortGroupKind, routes,
+		func(route *TCPRoute) []string {
+			return lo.FlatMap(route.Spec.Rules, func(rule gwapiv1.TCPRouteRule, _ int) []string {
+				return lo.FilterMap(rule.BackendRefs, servicePortKeyFromBackendRefFunc(route.Namespace, route.RefusedBackendRefs))
 			})
 		},
-	}
+		servicePortKeys,
+	)
 }
 
 // LinkTCPRouteToTCPRouteRuleFunc returns a link function that teaches a topology how to link TCPRouteRule from the
@@ -943,80 +2004,52 @@ func LinkTCPRouteToTCPRouteRuleFunc() LinkFunc {
 // TCPRouteRules, based on the TCPRouteRule's `backendRefs` field.
 // Set the `strict` parameter to `true` to link only to services that have no port specified in the backendRefs.
 func LinkTCPRouteRuleToServiceFunc(routeRules []*TCPRouteRule, strict bool) LinkFunc {
-	return LinkFunc{
-		From: TCPRouteRuleGroupKind,
-		To:   ServiceGroupKind,
-		Func: func(child Object) []Object {
-			service := child.(*Service)
-			return lo.FilterMap(routeRules, func(routeRule *TCPRouteRule, _ int) (Object, bool) {
-				backendRefs := lo.Filter(routeRule.BackendRefs, func(backendRef gwapiv1.BackendRef, _ int) bool {
-					return !strict || backendRef.Port == nil
-				})
-				return routeRule, lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(service, routeRule.TCPRoute.Namespace))
-			})
+	return IndexedLinkFunc(TCPRouteRuleGroupKind, ServiceGroupKind, routeRules,
+		func(routeRule *TCPRouteRule) []string {
+			return lo.FilterMap(routeRule.BackendRefs, serviceKeyFromBackendRefFunc(routeRule.TCPRoute.Namespace, strict, routeRule.TCPRoute.RefusedBackendRefs))
 		},
-	}
+		serviceKeys,
+	)
 }
 
 // LinkTCPRouteRuleToServicePortFunc returns a link function that teaches a topology how to link services ports from
 // known TCPRouteRules, based on the TCPRouteRule's `backendRefs` field.
 // The link function disregards backend references that do not specify a port number.
 func LinkTCPRouteRuleToServicePortFunc(routeRules []*TCPRouteRule) LinkFunc {
-	return LinkFunc{
-		From: TCPRouteRuleGroupKind,
-		To:   ServicePortGroupKind,
-		Func: func(child Object) []Object {
-			servicePort := child.(*ServicePort)
-			return lo.FilterMap(routeRules, func(routeRule *TCPRouteRule, _ int) (Object, bool) {
-				backendRefs := lo.Filter(routeRule.BackendRefs, func(backendRef gwapiv1.BackendRef, _ int) bool {
-					return backendRef.Port != nil && int32(*backendRef.Port) == servicePort.Port
-				})
-				return routeRule, lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(servicePort.Service, routeRule.TCPRoute.Namespace))
-			})
+	return IndexedLinkFunc(TCPRouteRuleGroupKind, ServicePortGroupKind, routeRules,
+		func(routeRule *TCPRouteRule) []string {
+			return lo.FilterMap(routeRule.BackendRefs, servicePortKeyFromBackendRefFunc(routeRule.TCPRoute.Namespace, routeRule.TCPRoute.RefusedBackendRefs))
 		},
-	}
+		servicePortKeys,
+	)
 }
 
 // LinkTLSRouteToServiceFunc returns a link function that teaches a topology how to link Services from known
 // TLSRoutes, based on the TLSRoute's `backendRefs` fields.
 // Set the `strict` parameter to `true` to link only to services that have no port specified in the backendRefs.
 func LinkTLSRouteToServiceFunc(routes []*TLSRoute, strict bool) LinkFunc {
-	return LinkFunc{
-		From: TLSRouteGroupKind,
-		To:   ServiceGroupKind,
-		Func: func(child Object) []Object {
-			service := child.(*Service)
-			return lo.FilterMap(routes, func(route *TLSRoute, _ int) (Object, bool) {
-				return route, lo.ContainsBy(route.Spec.Rules, func(rule gwapiv1.TLSRouteRule) bool {
-					backendRefs := lo.Filter(rule.BackendRefs, func(backendRef gwapiv1.BackendRef, _ int) bool {
-						return !strict || backendRef.Port == nil
-					})
-					return lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(service, route.Namespace))
-				})
+	return IndexedLinkFunc(TLSRouteGroupKind, ServiceGroupKind, routes,
+		func(route *TLSRoute) []string {
+			return lo.FlatMap(route.Spec.Rules, func(rule gwapiv1.TLSRouteRule, _ int) []string {
+				return lo.FilterMap(rule.BackendRefs, serviceKeyFromBackendRefFunc(route.Namespace, strict, route.RefusedBackendRefs))
 			})
 		},
-	}
+		serviceKeys,
+	)
 }
 
 // LinkTLSRouteToServicePortFunc returns a link function that teaches a topology how to link services ports from known
 // TLSRoutes, based on the TLSRoute's `backendRefs` fields.
 // The link function disregards backend references that do not specify a port number.
 func LinkTLSRouteToServicePortFunc(routes []*TLSRoute) LinkFunc {
-	return LinkFunc{
-		From: TLSRouteGroupKind,
-		To:   ServicePortGroupKind,
-		Func: func(child Object) []Object {
-			servicePort := child.(*ServicePort)
-			return lo.FilterMap(routes, func(route *TLSRoute, _ int) (Object, bool) {
-				return route, lo.ContainsBy(route.Spec.Rules, func(rule gwapiv1.TLSRouteRule) bool {
-					backendRefs := lo.Filter(rule.BackendRefs, func(backendRef gwapiv1.BackendRef, _ int) bool {
-						return backendRef.Port != nil && int32(*backendRef.Port) == servicePort.Port
-					})
-					return lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(servicePort.Service, route.Namespace))
-				})
+	return IndexedLinkFunc(TLSRouteGroupKind, ServicePortGroupKind, routes,
+		func(route *TLSRoute) []string {
+			return lo.FlatMap(route.Spec.Rules, func(rule gwapiv1.TLSRouteRule, _ int) []string {
+				return lo.FilterMap(rule.BackendRefs, servicePortKeyFromBackendRefFunc(route.Namespace, route.RefusedBackendRefs))
 			})
 		},
-	}
+		servicePortKeys,
+	)
 }
 
 // LinkTLSRouteToTLSRouteRuleFunc returns a link function that teaches a topology how to link TLSRouteRule from the
@@ -1036,80 +2069,52 @@ func LinkTLSRouteToTLSRouteRuleFunc() LinkFunc {
 // TLSRouteRules, based on the TLSRouteRule's `backendRefs` field.
 // Set the `strict` parameter to `true` to link only to services that have no port specified in the backendRefs.
 func LinkTLSRouteRuleToServiceFunc(routeRules []*TLSRouteRule, strict bool) LinkFunc {
-	return LinkFunc{
-		From: TLSRouteRuleGroupKind,
-		To:   ServiceGroupKind,
-		Func: func(child Object) []Object {
-			service := child.(*Service)
-			return lo.FilterMap(routeRules, func(routeRule *TLSRouteRule, _ int) (Object, bool) {
-				backendRefs := lo.Filter(routeRule.BackendRefs, func(backendRef gwapiv1.BackendRef, _ int) bool {
-					return !strict || backendRef.Port == nil
-				})
-				return routeRule, lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(service, routeRule.TLSRoute.Namespace))
-			})
+	return IndexedLinkFunc(TLSRouteRuleGroupKind, ServiceGroupKind, routeRules,
+		func(routeRule *TLSRouteRule) []string {
+			return lo.FilterMap(routeRule.BackendRefs, serviceKeyFromBackendRefFunc(routeRule.TLSRoute.Namespace, strict, routeRule.TLSRoute.RefusedBackendRefs))
 		},
-	}
+		serviceKeys,
+	)
 }
 
 // LinkTLSRouteRuleToServicePortFunc returns a link function that teaches a topology how to link services ports from
 // known TLSRouteRules, based on the TLSRouteRule's `backendRefs` field.
 // The link function disregards backend references that do not specify a port number.
 func LinkTLSRouteRuleToServicePortFunc(routeRules []*TLSRouteRule) LinkFunc {
-	return LinkFunc{
-		From: TLSRouteRuleGroupKind,
-		To:   ServicePortGroupKind,
-		Func: func(child Object) []Object {
-			servicePort := child.(*ServicePort)
-			return lo.FilterMap(routeRules, func(routeRule *TLSRouteRule, _ int) (Object, bool) {
-				backendRefs := lo.Filter(routeRule.BackendRefs, func(backendRef gwapiv1.BackendRef, _ int) bool {
-					return backendRef.Port != nil && int32(*backendRef.Port) == servicePort.Port
-				})
-				return routeRule, lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(servicePort.Service, routeRule.TLSRoute.Namespace))
-			})
+	return IndexedLinkFunc(TLSRouteRuleGroupKind, ServicePortGroupKind, routeRules,
+		func(routeRule *TLSRouteRule) []string {
+			return lo.FilterMap(routeRule.BackendRefs, servicePortKeyFromBackendRefFunc(routeRule.TLSRoute.Namespace, routeRule.TLSRoute.RefusedBackendRefs))
 		},
-	}
+		servicePortKeys,
+	)
 }
 
 // LinkUDPRouteToServiceFunc returns a link function that teaches a topology how to link Services from known
 // UDPRoutes, based on the UDPRoute's `backendRefs` fields.
 // Set the `strict` parameter to `true` to link only to services that have no port specified in the backendRefs.
 func LinkUDPRouteToServiceFunc(routes []*UDPRoute, strict bool) LinkFunc {
-	return LinkFunc{
-		From: UDPRouteGroupKind,
-		To:   ServiceGroupKind,
-		Func: func(child Object) []Object {
-			service := child.(*Service)
-			return lo.FilterMap(routes, func(route *UDPRoute, _ int) (Object, bool) {
-				return route, lo.ContainsBy(route.Spec.Rules, func(rule gwapiv1.UDPRouteRule) bool {
-					backendRefs := lo.Filter(rule.BackendRefs, func(backendRef gwapiv1.BackendRef, _ int) bool {
-						return !strict || backendRef.Port == nil
-					})
-					return lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(service, route.Namespace))
-				})
+	return IndexedLinkFunc(UDPRouteGroupKind, ServiceGroupKind, routes,
+		func(route *UDPRoute) []string {
+			return lo.FlatMap(route.Spec.Rules, func(rule gwapiv1.UDPRouteRule, _ int) []string {
+				return lo.FilterMap(rule.BackendRefs, serviceKeyFromBackendRefFunc(route.Namespace, strict, route.RefusedBackendRefs))
 			})
 		},
-	}
+		serviceKeys,
+	)
 }
 
 // LinkUDPRouteToServicePortFunc returns a link function that teaches a topology how to link services ports from known
 // UDPRoutes, based on the UDPRoute's `backendRefs` fields.
 // The link function disregards backend references that do not specify a port number.
 func LinkUDPRouteToServicePortFunc(routes []*UDPRoute) LinkFunc {
-	return LinkFunc{
-		From: UDPRouteGroupKind,
-		To:   ServicePortGroupKind,
-		Func: func(child Object) []Object {
-			servicePort := child.(*ServicePort)
-			return lo.FilterMap(routes, func(route *UDPRoute, _ int) (Object, bool) {
-				return route, lo.ContainsBy(route.Spec.Rules, func(rule gwapiv1.UDPRouteRule) bool {
-					backendRefs := lo.Filter(rule.BackendRefs, func(backendRef gwapiv1.BackendRef, _ int) bool {
-						return backendRef.Port != nil && int32(*backendRef.Port) == servicePort.Port
-					})
-					return lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(servicePort.Service, route.Namespace))
-				})
+	return IndexedLinkFunc(UDPRouteGroupKind, ServicePortGroupKind, routes,
+		func(route *UDPRoute) []string {
+			return lo.FlatMap(route.Spec.Rules, func(rule gwapiv1.UDPRouteRule, _ int) []string {
+				return lo.FilterMap(rule.BackendRefs, servicePortKeyFromBackendRefFunc(route.Namespace, route.RefusedBackendRefs))
 			})
 		},
-	}
+		servicePortKeys,
+	)
 }
 
 // LinkUDPRouteToUDPRouteRuleFunc returns a link function that teaches a topology how to link UDPRouteRule from the
@@ -1129,38 +2134,24 @@ func LinkUDPRouteToUDPRouteRuleFunc() LinkFunc {
 // UDPRouteRules, based on the UDPRouteRule's `backendRefs` field.
 // Set the `strict` parameter to `true` to link only to services that have no port specified in the backendRefs.
 func LinkUDPRouteRuleToServiceFunc(routeRules []*UDPRouteRule, strict bool) LinkFunc {
-	return LinkFunc{
-		From: UDPRouteRuleGroupKind,
-		To:   ServiceGroupKind,
-		Func: func(child Object) []Object {
-			service := child.(*Service)
-			return lo.FilterMap(routeRules, func(routeRule *UDPRouteRule, _ int) (Object, bool) {
-				backendRefs := lo.Filter(routeRule.BackendRefs, func(backendRef gwapiv1.BackendRef, _ int) bool {
-					return !strict || backendRef.Port == nil
-				})
-				return routeRule, lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(service, routeRule.UDPRoute.Namespace))
-			})
+	return IndexedLinkFunc(UDPRouteRuleGroupKind, ServiceGroupKind, routeRules,
+		func(routeRule *UDPRouteRule) []string {
+			return lo.FilterMap(routeRule.BackendRefs, serviceKeyFromBackendRefFunc(routeRule.UDPRoute.Namespace, strict, routeRule.UDPRoute.RefusedBackendRefs))
 		},
-	}
+		serviceKeys,
+	)
 }
 
 // LinkUDPRouteRuleToServicePortFunc returns a link function that teaches a topology how to link services ports from
 // known UDPRouteRules, based on the UDPRouteRule's `backendRefs` field.
 // The link function disregards backend references that do not specify a port number.
 func LinkUDPRouteRuleToServicePortFunc(routeRules []*UDPRouteRule) LinkFunc {
-	return LinkFunc{
-		From: UDPRouteRuleGroupKind,
-		To:   ServicePortGroupKind,
-		Func: func(child Object) []Object {
-			servicePort := child.(*ServicePort)
-			return lo.FilterMap(routeRules, func(routeRule *UDPRouteRule, _ int) (Object, bool) {
-				backendRefs := lo.Filter(routeRule.BackendRefs, func(backendRef gwapiv1.BackendRef, _ int) bool {
-					return backendRef.Port != nil && int32(*backendRef.Port) == servicePort.Port
-				})
-				return routeRule, lo.ContainsBy(backendRefs, backendRefContainsServiceFunc(servicePort.Service, routeRule.UDPRoute.Namespace))
-			})
+	return IndexedLinkFunc(UDPRouteRuleGroupKind, ServicePortGroupKind, routeRules,
+		func(routeRule *UDPRouteRule) []string {
+			return lo.FilterMap(routeRule.BackendRefs, servicePortKeyFromBackendRefFunc(routeRule.UDPRoute.Namespace, routeRule.UDPRoute.RefusedBackendRefs))
 		},
-	}
+		servicePortKeys,
+	)
 }
 
 // LinkServiceToServicePortFunc returns a link function that teaches a topology how to link service ports from the
@@ -1176,15 +2167,346 @@ func LinkServiceToServicePortFunc() LinkFunc {
 	}
 }
 
-func backendRefContainsServiceFunc(service *Service, defaultNamespace string) func(backendRef gwapiv1.BackendRef) bool {
-	return func(backendRef gwapiv1.BackendRef) bool {
-		return backendRefEqualToService(backendRef, service, defaultNamespace)
+// LinkServiceToEndpointSliceFunc returns a link function that teaches a topology how to link EndpointSlices from known
+// Services, based on the `kubernetes.io/service-name` label of the EndpointSlices.
+// Set the `strict` parameter to `true` to link only the EndpointSlices that specify no ports.
+func LinkServiceToEndpointSliceFunc(services []*Service, strict bool) LinkFunc {
+	return IndexedLinkFunc(ServiceGroupKind, EndpointSliceGroupKind, services,
+		func(service *Service) []string {
+			return []string{serviceKey(service)}
+		},
+		func(child Object) []string {
+			endpointSlice := child.(*EndpointSlice)
+			key, ok := serviceKeyFromEndpointSlice(endpointSlice)
+			if !ok || (strict && len(endpointSlice.Ports) > 0) {
+				return nil
+			}
+			return []string{key}
+		},
+	)
+}
+
+// LinkServicePortToEndpointSliceFunc returns a link function that teaches a topology how to link EndpointSlices from
+// known service ports, based on the `kubernetes.io/service-name` label of the EndpointSlices and the names of their
+// ports.
+func LinkServicePortToEndpointSliceFunc(servicePorts []*ServicePort) LinkFunc {
+	return IndexedLinkFunc(ServicePortGroupKind, EndpointSliceGroupKind, servicePorts,
+		func(servicePort *ServicePort) []string {
+			return []string{namespacedSectionName(serviceKey(servicePort.Service), gwapiv1.SectionName(servicePort.Name))}
+		},
+		func(child Object) []string {
+			endpointSlice := child.(*EndpointSlice)
+			key, ok := serviceKeyFromEndpointSlice(endpointSlice)
+			if !ok {
+				return nil
+			}
+			return lo.Map(endpointSlice.Ports, func(port discovery.EndpointPort, _ int) string {
+				return namespacedSectionName(key, gwapiv1.SectionName(ptr.Deref(port.Name, "")))
+			})
+		},
+	)
+}
+
+// LinkEndpointSliceToEndpointZoneFunc returns a link function that teaches a topology how to link endpoint zones from
+// the EndpointSlices they belong to.
+func LinkEndpointSliceToEndpointZoneFunc() LinkFunc {
+	return LinkFunc{
+		From: EndpointSliceGroupKind,
+		To:   EndpointZoneGroupKind,
+		Func: func(child Object) []Object {
+			endpointZone := child.(*EndpointZone)
+			return []Object{endpointZone.EndpointSlice}
+		},
+	}
+}
+
+// LinkToBackendFunc returns a link function that teaches a topology how to link the backends of a registered kind from
+// known routes or route rules, based on their `backendRefs` fields.
+// When the backends of the kind are expanded into ports, only backendRefs that do not specify a port are linked.
+func LinkToBackendFunc(from schema.GroupKind, parents []Object, backendKind BackendKind) LinkFunc {
+	return IndexedLinkFunc(from, backendKind.GroupKind, parents,
+		func(parent Object) []string {
+			backendRefs, namespace, refused := backendRefsFromRouteOrRule(parent)
+			return lo.FilterMap(backendRefs, serviceKeyFromBackendRefFunc(namespace, backendKind.Ports != nil, refused))
+		},
+		func(child Object) []string {
+			return []string{backendKindKey(backendKind, child)}
+		},
+	)
+}
+
+// LinkToBackendPortFunc returns a link function that teaches a topology how to link the ports of the backends of a
+// registered kind from known routes or route rules, based on their `backendRefs` fields.
+// The link function disregards backend references that do not specify a port number.
+func LinkToBackendPortFunc(from schema.GroupKind, parents []Object, backendKind BackendKind) LinkFunc {
+	return IndexedLinkFunc(from, backendKind.PortGroupKind(), parents,
+		func(parent Object) []string {
+			backendRefs, namespace, refused := backendRefsFromRouteOrRule(parent)
+			return lo.FilterMap(backendRefs, servicePortKeyFromBackendRefFunc(namespace, refused))
+		},
+		func(child Object) []string {
+			backendPort := child.(*BackendPort)
+			return []string{portKey(backendKindKey(backendKind, backendPort.Backend), backendPort.Port)}
+		},
+	)
+}
+
+// LinkBackendToBackendPortFunc returns a link function that teaches a topology how to link the ports of the backends
+// of a registered kind from the backends they belong to.
+func LinkBackendToBackendPortFunc(backendKind BackendKind) LinkFunc {
+	return LinkFunc{
+		From: backendKind.GroupKind,
+		To:   backendKind.PortGroupKind(),
+		Func: func(child Object) []Object {
+			backendPort := child.(*BackendPort)
+			return []Object{backendPort.Backend}
+		},
 	}
 }
 
-func backendRefEqualToService(backendRef gwapiv1.BackendRef, service *Service, defaultNamespace string) bool {
+// backendRefsFromRouteOrRule returns the backendRefs of a route or route rule, along with the namespace of the route
+// and the backendRefs of the route refused for lack of ReferenceGrants
+func backendRefsFromRouteOrRule(obj Object) ([]gwapiv1.BackendRef, string, []gwapiv1.BackendRef) {
+	switch r := obj.(type) {
+	case *HTTPRouteRule:
+		return lo.Map(r.BackendRefs, backendRefFromHTTPBackendRef), r.HTTPRoute.Namespace, r.HTTPRoute.RefusedBackendRefs
+	case *GRPCRouteRule:
+		return lo.Map(r.BackendRefs, backendRefFromGRPCBackendRef), r.GRPCRoute.Namespace, r.GRPCRoute.RefusedBackendRefs
+	case *TCPRouteRule:
+		return r.BackendRefs, r.TCPRoute.Namespace, r.TCPRoute.RefusedBackendRefs
+	case *TLSRouteRule:
+		return r.BackendRefs, r.TLSRoute.Namespace, r.TLSRoute.RefusedBackendRefs
+	case *UDPRouteRule:
+		return r.BackendRefs, r.UDPRoute.Namespace, r.UDPRoute.RefusedBackendRefs
+	case *HTTPRoute:
+		return backendRefsFromRoute(r), r.Namespace, r.RefusedBackendRefs
+	case *GRPCRoute:
+		return backendRefsFromRoute(r), r.Namespace, r.RefusedBackendRefs
+	case *TCPRoute:
+		return backendRefsFromRoute(r), r.Namespace, r.RefusedBackendRefs
+	case *TLSRoute:
+		return backendRefsFromRoute(r), r.Namespace, r.RefusedBackendRefs
+	case *UDPRoute:
+		return backendRefsFromRoute(r), r.Namespace, r.RefusedBackendRefs
+	}
+	return nil, "", nil
+}
+
+// backendKindKey returns the index key of a backend of a registered kind
+func backendKindKey(backendKind BackendKind, backend Object) string {
+	return backendKey(backendKind.GroupKind.Group, backendKind.GroupKind.Kind, backend.GetNamespace(), backend.GetName())
+}
+
+func asObject[T Object](obj T, _ int) Object {
+	return obj
+}
+
+// LinkServiceToHTTPRouteFunc returns a link function that teaches a topology how to link mesh HTTPRoutes from known
+// Services, based on the HTTPRoute's `parentRefs` field.
+// Set the `strict` parameter to `true` to link only the HTTPRoutes whose parent references specify neither a port
+// number nor a port name.
+func LinkServiceToHTTPRouteFunc(services []*Service, strict bool) LinkFunc {
+	return linkServiceToMeshRouteFunc(services, HTTPRouteGroupKind, strict)
+}
+
+// LinkServiceToGRPCRouteFunc returns a link function that teaches a topology how to link mesh GRPCRoutes from known
+// Services, based on the GRPCRoute's `parentRefs` field.
+// Set the `strict` parameter to `true` to link only the GRPCRoutes whose parent references specify neither a port
+// number nor a port name.
+func LinkServiceToGRPCRouteFunc(services []*Service, strict bool) LinkFunc {
+	return linkServiceToMeshRouteFunc(services, GRPCRouteGroupKind, strict)
+}
+
+// LinkServicePortToHTTPRouteFunc returns a link function that teaches a topology how to link mesh HTTPRoutes from
+// known service ports, based on the `port` and `sectionName` fields of the HTTPRoute's `parentRefs`.
+// The link function disregards parent references that specify neither a port number nor a port name.
+func LinkServicePortToHTTPRouteFunc(servicePorts []*ServicePort) LinkFunc {
+	return linkServicePortToMeshRouteFunc(servicePorts, HTTPRouteGroupKind)
+}
+
+// LinkServicePortToGRPCRouteFunc returns a link function that teaches a topology how to link mesh GRPCRoutes from
+// known service ports, based on the `port` and `sectionName` fields of the GRPCRoute's `parentRefs`.
+// The link function disregards parent references that specify neither a port number nor a port name.
+func LinkServicePortToGRPCRouteFunc(servicePorts []*ServicePort) LinkFunc {
+	return linkServicePortToMeshRouteFunc(servicePorts, GRPCRouteGroupKind)
+}
+
+func linkServiceToMeshRouteFunc(services []*Service, to schema.GroupKind, strict bool) LinkFunc {
+	return IndexedLinkFunc(ServiceGroupKind, to, services, func(service *Service) []string {
+		return []string{serviceKey(service)}
+	}, func(child Object) []string {
+		return lo.FilterMap(parentRefsFromRoute(child), func(parentRef gwapiv1.ParentReference, _ int) (string, bool) {
+			key, ok := serviceKeyFromParentRef(parentRef, child.GetNamespace())
+			return key, ok && (!strict || (parentRef.Port == nil && parentRef.SectionName == nil))
+		})
+	})
+}
+
+func linkServicePortToMeshRouteFunc(servicePorts []*ServicePort, to schema.GroupKind) LinkFunc {
+	return IndexedLinkFunc(ServicePortGroupKind, to, servicePorts, func(servicePort *ServicePort) []string {
+		key := serviceKey(servicePort.Service)
+		return []string{portKey(key, servicePort.Port), namespacedSectionName(key, gwapiv1.SectionName(servicePort.Name))}
+	}, func(child Object) []string {
+		return lo.FilterMap(parentRefsFromRoute(child), func(parentRef gwapiv1.ParentReference, _ int) (string, bool) {
+			key, ok := serviceKeyFromParentRef(parentRef, child.GetNamespace())
+			switch {
+			case !ok:
+				return "", false
+			case parentRef.Port != nil:
+				return portKey(key, int32(*parentRef.Port)), true
+			case parentRef.SectionName != nil:
+				return namespacedSectionName(key, *parentRef.SectionName), true
+			}
+			return "", false
+		})
+	})
+}
+
+// skipMeshParentBackends filters the links from mesh routes and their rules to Services and service ports, so that a
+// route is not linked to a Service it is attached to as a parent, which would form a loop in the topology.
+// It must be applied after all the other links are added to the options.
+func skipMeshParentBackends() TopologyOptionsFunc {
+	return func(o *TopologyOptions) {
+		o.Links = lo.Map(o.Links, func(link LinkFunc, _ int) LinkFunc {
+			if link.To != ServiceGroupKind && link.To != ServicePortGroupKind {
+				return link
+			}
+			return filterLinkFunc(link, func(parent, child Object) bool {
+				var route Object
+				switch r := parent.(type) {
+				case *HTTPRoute, *GRPCRoute:
+					route = r
+				case *HTTPRouteRule:
+					route = r.HTTPRoute
+				case *GRPCRouteRule:
+					route = r.GRPCRoute
+				default:
+					return true
+				}
+				var key string
+				switch c := child.(type) {
+				case *Service:
+					key = serviceKey(c)
+				case *ServicePort:
+					key = serviceKey(c.Service)
+				default:
+					return true
+				}
+				return !lo.ContainsBy(parentRefsFromRoute(route), func(parentRef gwapiv1.ParentReference) bool {
+					parentKey, ok := serviceKeyFromParentRef(parentRef, route.GetNamespace())
+					return ok && parentKey == key
+				})
+			})
+		})
+	}
+}
+
+// serviceKeyFromParentRef returns the index key of the Service a parent reference of a mesh route points to,
+// regardless of port
+func serviceKeyFromParentRef(parentRef gwapiv1.ParentReference, routeNamespace string) (string, bool) {
+	if ptr.Deref(parentRef.Group, gwapiv1.GroupName) != gwapiv1.Group(ServiceGroupKind.Group) || ptr.Deref(parentRef.Kind, "Gateway") != gwapiv1.Kind(ServiceGroupKind.Kind) {
+		return "", false
+	}
+	serviceNamespace := string(ptr.Deref(parentRef.Namespace, gwapiv1.Namespace(routeNamespace)))
+	return backendKey(ServiceGroupKind.Group, ServiceGroupKind.Kind, serviceNamespace, string(parentRef.Name)), true
+}
+
+// serviceKeyFromBackendRefFunc returns a function that returns the index key of the Service referred in a backendRef.
+// Set the `strict` parameter to `true` to disregard backendRefs that specify a port.
+func serviceKeyFromBackendRefFunc(defaultNamespace string, strict bool, refused []gwapiv1.BackendRef) func(backendRef gwapiv1.BackendRef, _ int) (string, bool) {
+	return func(backendRef gwapiv1.BackendRef, _ int) (string, bool) {
+		key := backendRefKey(backendRef, defaultNamespace)
+		return key, (!strict || backendRef.Port == nil) && !isRefusedBackendRef(key, refused, defaultNamespace)
+	}
+}
+
+// servicePortKeyFromBackendRefFunc returns a function that returns the index key of the service port referred in a
+// backendRef. BackendRefs that do not specify a port number are disregarded.
+func servicePortKeyFromBackendRefFunc(defaultNamespace string, refused []gwapiv1.BackendRef) func(backendRef gwapiv1.BackendRef, _ int) (string, bool) {
+	return func(backendRef gwapiv1.BackendRef, _ int) (string, bool) {
+		if backendRef.Port == nil {
+			return "", false
+		}
+		key := backendRefKey(backendRef, defaultNamespace)
+		if isRefusedBackendRef(key, refused, defaultNamespace) {
+			return "", false
+		}
+		return portKey(key, int32(*backendRef.Port)), true
+	}
+}
+
+// isRefusedBackendRef tells whether the backend with the given index key is referred in any of the refused backendRefs
+func isRefusedBackendRef(key string, refused []gwapiv1.BackendRef, defaultNamespace string) bool {
+	return lo.ContainsBy(refused, func(backendRef gwapiv1.BackendRef) bool {
+		return backendRefKey(backendRef, defaultNamespace) == key
+	})
+}
+
+// refusedBackendRefsFunc returns a function that returns the backendRefs of a route to objects in other namespaces that
+// are not permitted by any of the ReferenceGrants, indexed by namespace
+func refusedBackendRefsFunc(referenceGrants map[string][]*ReferenceGrant) func(from schema.GroupKind, namespace string, backendRefs []gwapiv1.BackendRef) []gwapiv1.BackendRef {
+	return func(from schema.GroupKind, namespace string, backendRefs []gwapiv1.BackendRef) []gwapiv1.BackendRef {
+		return lo.Filter(backendRefs, func(backendRef gwapiv1.BackendRef, _ int) bool {
+			backendRefNamespace := string(ptr.Deref(backendRef.Namespace, gwapiv1.Namespace(namespace)))
+			if backendRefNamespace == namespace {
+				return false
+			}
+			to := schema.GroupKind{
+				Group: string(ptr.Deref(backendRef.Group, gwapiv1.Group(""))),
+				Kind:  string(ptr.Deref(backendRef.Kind, gwapiv1.Kind("Service"))),
+			}
+			return !lo.ContainsBy(referenceGrants[backendRefNamespace], func(referenceGrant *ReferenceGrant) bool {
+				return referenceGrant.Permits(from, namespace, to, string(backendRef.Name))
+			})
+		})
+	}
+}
+
+// serviceKeys returns the index keys of a Service
+func serviceKeys(child Object) []string {
+	service := child.(*Service)
+	return []string{serviceKey(service)}
+}
+
+// servicePortKeys returns the index keys of a service port
+func servicePortKeys(child Object) []string {
+	servicePort := child.(*ServicePort)
+	return []string{portKey(serviceKey(servicePort.Service), servicePort.Port)}
+}
+
+// serviceKeyFromEndpointSlice returns the index key of the Service named in the `kubernetes.io/service-name` label of an
+// EndpointSlice
+func serviceKeyFromEndpointSlice(endpointSlice *EndpointSlice) (string, bool) {
+	serviceName := endpointSlice.Labels[discovery.LabelServiceName]
+	if serviceName == "" {
+		return "", false
+	}
+	return backendKey(ServiceGroupKind.Group, ServiceGroupKind.Kind, endpointSlice.Namespace, serviceName), true
+}
+
+func serviceKey(service *Service) string {
+	return backendKey(service.GroupVersionKind().Group, service.GroupVersionKind().Kind, service.Namespace, service.Name)
+}
+
+func backendRefKey(backendRef gwapiv1.BackendRef, defaultNamespace string) string {
 	backendRefGroup := string(ptr.Deref(backendRef.Group, gwapiv1.Group("")))
 	backendRefKind := string(ptr.Deref(backendRef.Kind, gwapiv1.Kind("Service")))
 	backendRefNamespace := string(ptr.Deref(backendRef.Namespace, gwapiv1.Namespace(defaultNamespace)))
-	return backendRefGroup == service.GroupVersionKind().Group && backendRefKind == service.GroupVersionKind().Kind && backendRefNamespace == service.Namespace && string(backendRef.Name) == service.Name
+	return backendKey(backendRefGroup, backendRefKind, backendRefNamespace, string(backendRef.Name))
+}
+
+func backendKey(group, kind, namespace, name string) string {
+	return fmt.Sprintf("%s/%s/%s", group, kind, namespacedName(namespace, name))
+}
+
+func portKey(key string, port int32) string {
+	return fmt.Sprintf("%s:%d", key, port)
+}
+
+func backendRefFromHTTPBackendRef(backendRef gwapiv1.HTTPBackendRef, _ int) gwapiv1.BackendRef {
+	return backendRef.BackendRef
+}
+
+func backendRefFromGRPCBackendRef(backendRef gwapiv1.GRPCBackendRef, _ int) gwapiv1.BackendRef {
+	return backendRef.BackendRef
 }
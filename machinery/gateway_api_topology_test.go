@@ -3,13 +3,19 @@
 package machinery
 
 import (
+	"fmt"
+	"regexp"
 	"slices"
 	"testing"
 
 	"github.com/samber/lo"
 	core "k8s.io/api/core/v1"
+	discovery "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/utils/ptr"
 	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gwapiv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
 )
 
 // TestGatewayAPITopology tests for a simplified topology of Gateway API resources without section names,
@@ -396,6 +402,1781 @@ func TestGatewayAPITopologyWithSectionNames(t *testing.T) {
 	}
 }
 
+// TestGatewayAPITopologyWithNamespaces tests for a topology of Gateway API resources where Namespaces are added as
+// targetables and linked to the Gateways and routes that live in them.
+//
+// This results in a topology with the following scheme:
+//
+//	GatewayClass -> Gateway -> HTTPRoute -> Service
+//	   Namespace ⤴         ∟> GRPCRoute ⤴
+//	   Namespace ------------⤴
+func TestGatewayAPITopologyWithNamespaces(t *testing.T) {
+	namespacePolicy := buildPolicy(func(p *TestPolicy) {
+		p.Name = "namespace-policy"
+		p.Spec.TargetRef.Group = gwapiv1.Group(core.SchemeGroupVersion.Group)
+		p.Spec.TargetRef.Kind = "Namespace"
+		p.Spec.TargetRef.Name = "my-namespace"
+	})
+
+	topology, err := NewGatewayAPITopology(
+		WithNamespaces(
+			BuildNamespace(),
+			BuildNamespace(func(ns *core.Namespace) { ns.Name = "other-namespace" }),
+		),
+		WithGatewayClasses(BuildGatewayClass()),
+		WithGateways(BuildGateway()),
+		WithHTTPRoutes(BuildHTTPRoute()),
+		WithGRPCRoutes(BuildGRPCRoute(func(r *gwapiv1.GRPCRoute) {
+			r.Namespace = "other-namespace"
+			r.Spec.ParentRefs[0].Namespace = ptr.To(gwapiv1.Namespace("my-namespace"))
+			r.Spec.Rules[0].BackendRefs[0].Namespace = ptr.To(gwapiv1.Namespace("my-namespace"))
+		})),
+		WithServices(BuildService()),
+		WithGatewayAPITopologyPolicies(namespacePolicy),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expectedLinks := map[string][]string{
+		"my-gateway-class": {"my-gateway"},
+		"my-namespace":     {"my-gateway", "my-http-route"},
+		"other-namespace":  {"my-grpc-route"},
+		"my-gateway":       {"my-http-route", "my-grpc-route"},
+		"my-http-route":    {"my-service"},
+		"my-grpc-route":    {"my-service"},
+	}
+	links := make(map[string][]string)
+	for _, root := range topology.Targetables().Roots() {
+		linksFromTargetable(topology, root, links)
+	}
+	for from, tos := range links {
+		expectedTos := expectedLinks[from]
+		slices.Sort(expectedTos)
+		slices.Sort(tos)
+		if !slices.Equal(expectedTos, tos) {
+			t.Errorf("expected links from %s to be %v, got %v", from, expectedTos, tos)
+		}
+	}
+
+	namespace, found := lo.Find(topology.Targetables().Items(), func(t Targetable) bool {
+		return t.GetLocator() == "namespace:my-namespace"
+	})
+	if !found {
+		t.Fatalf("expected namespace my-namespace in the topology")
+	}
+	if policies := namespace.Policies(); len(policies) != 1 || policies[0].GetLocator() != namespacePolicy.GetLocator() {
+		t.Errorf("expected policy %s attached to namespace my-namespace, got %v", namespacePolicy.GetLocator(), policies)
+	}
+	service, _ := lo.Find(topology.Targetables().Items(), func(t Targetable) bool {
+		return t.GetLocator() == "service:my-namespace/my-service"
+	})
+	// namespace -> gateway -> http route -> service, namespace -> gateway -> grpc route -> service, namespace -> http route -> service
+	if paths := topology.Targetables().Paths(namespace, service); len(paths) != 3 {
+		t.Errorf("expected 3 paths from namespace my-namespace to service my-service, got %d", len(paths))
+	}
+
+	SaveToOutputDir(t, topology.ToDot(), "../tests/out", ".dot")
+}
+
+// TestGatewayAPITopologyWithReferenceGrants tests for a topology of Gateway API resources where HTTPRoutes refer to
+// Services in other namespaces, and ReferenceGrants are required for the links to be established.
+func TestGatewayAPITopologyWithReferenceGrants(t *testing.T) {
+	httpRoute := BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+		r.Namespace = "other-namespace"
+		r.Spec.Rules[0].BackendRefs = []gwapiv1.HTTPBackendRef{
+			BuildHTTPBackendRef(func(backendRef *gwapiv1.BackendObjectReference) {
+				backendRef.Namespace = ptr.To(gwapiv1.Namespace("my-namespace"))
+				backendRef.Port = ptr.To(gwapiv1.PortNumber(80))
+			}),
+		}
+	})
+
+	testCases := []struct {
+		name                   string
+		referenceGrants        []*gwapiv1beta1.ReferenceGrant
+		requireReferenceGrants bool
+		expectedLinks          map[string][]string
+		expectedRefused        int
+	}{
+		{
+			name: "not required",
+			expectedLinks: map[string][]string{
+				"my-http-route":        {"my-http-route#rule-1"},
+				"my-http-route#rule-1": {"my-service#http"},
+				"my-service":           {"my-service#http"},
+			},
+		},
+		{
+			name:                   "required without reference grants",
+			requireReferenceGrants: true,
+			expectedLinks: map[string][]string{
+				"my-http-route": {"my-http-route#rule-1"},
+				"my-service":    {"my-service#http"},
+			},
+			expectedRefused: 1,
+		},
+		{
+			name:                   "required with reference grant to all services",
+			referenceGrants:        []*gwapiv1beta1.ReferenceGrant{BuildReferenceGrant()},
+			requireReferenceGrants: true,
+			expectedLinks: map[string][]string{
+				"my-http-route":        {"my-http-route#rule-1"},
+				"my-http-route#rule-1": {"my-service#http"},
+				"my-service":           {"my-service#http"},
+			},
+		},
+		{
+			name: "required with reference grant to the service",
+			referenceGrants: []*gwapiv1beta1.ReferenceGrant{BuildReferenceGrant(func(rg *gwapiv1beta1.ReferenceGrant) {
+				rg.Spec.To[0].Name = ptr.To(gwapiv1.ObjectName("my-service"))
+			})},
+			requireReferenceGrants: true,
+			expectedLinks: map[string][]string{
+				"my-http-route":        {"my-http-route#rule-1"},
+				"my-http-route#rule-1": {"my-service#http"},
+				"my-service":           {"my-service#http"},
+			},
+		},
+		{
+			name: "required with reference grant to another service",
+			referenceGrants: []*gwapiv1beta1.ReferenceGrant{BuildReferenceGrant(func(rg *gwapiv1beta1.ReferenceGrant) {
+				rg.Spec.To[0].Name = ptr.To(gwapiv1.ObjectName("other-service"))
+			})},
+			requireReferenceGrants: true,
+			expectedLinks: map[string][]string{
+				"my-http-route": {"my-http-route#rule-1"},
+				"my-service":    {"my-service#http"},
+			},
+			expectedRefused: 1,
+		},
+		{
+			name: "required with reference grant from another namespace",
+			referenceGrants: []*gwapiv1beta1.ReferenceGrant{BuildReferenceGrant(func(rg *gwapiv1beta1.ReferenceGrant) {
+				rg.Spec.From[0].Namespace = "another-namespace"
+			})},
+			requireReferenceGrants: true,
+			expectedLinks: map[string][]string{
+				"my-http-route": {"my-http-route#rule-1"},
+				"my-service":    {"my-service#http"},
+			},
+			expectedRefused: 1,
+		},
+		{
+			name: "required with reference grant in the namespace of the route",
+			referenceGrants: []*gwapiv1beta1.ReferenceGrant{BuildReferenceGrant(func(rg *gwapiv1beta1.ReferenceGrant) {
+				rg.Namespace = "other-namespace"
+			})},
+			requireReferenceGrants: true,
+			expectedLinks: map[string][]string{
+				"my-http-route": {"my-http-route#rule-1"},
+				"my-service":    {"my-service#http"},
+			},
+			expectedRefused: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options := []GatewayAPITopologyOptionsFunc{
+				WithHTTPRoutes(httpRoute),
+				WithServices(BuildService()),
+				WithReferenceGrants(tc.referenceGrants...),
+				ExpandHTTPRouteRules(),
+				ExpandServicePorts(),
+			}
+			if tc.requireReferenceGrants {
+				options = append(options, RequireReferenceGrants())
+			}
+
+			topology, err := NewGatewayAPITopology(options...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			links := make(map[string][]string)
+			for _, root := range topology.Targetables().Roots() {
+				linksFromTargetable(topology, root, links)
+			}
+			for from, tos := range links {
+				expectedTos := tc.expectedLinks[from]
+				slices.Sort(expectedTos)
+				slices.Sort(tos)
+				if !slices.Equal(expectedTos, tos) {
+					t.Errorf("expected links from %s to be %v, got %v", from, expectedTos, tos)
+				}
+			}
+
+			route, found := lo.Find(topology.Targetables().Items(), func(t Targetable) bool {
+				return t.GetLocator() == "httproute.gateway.networking.k8s.io:other-namespace/my-http-route"
+			})
+			if !found {
+				t.Fatalf("expected http route my-http-route in the topology")
+			}
+			if refused := route.(*HTTPRoute).RefusedBackendRefs; len(refused) != tc.expectedRefused {
+				t.Errorf("expected %d refused backendRefs, got %d", tc.expectedRefused, len(refused))
+			}
+			if objects := topology.Objects().Items(); len(objects) != len(tc.referenceGrants) {
+				t.Errorf("expected %d reference grants in the topology, got %d", len(tc.referenceGrants), len(objects))
+			}
+		})
+	}
+}
+
+// TestGatewayAPITopologyWithAllowedRoutes tests for a topology of Gateway API resources where the links from Gateways
+// and Listeners to routes are restricted to the ones allowed by the Listeners.
+func TestGatewayAPITopologyWithAllowedRoutes(t *testing.T) {
+	allNamespaces := &gwapiv1.AllowedRoutes{Namespaces: &gwapiv1.RouteNamespaces{From: ptr.To(gwapiv1.NamespacesFromAll)}}
+
+	gateway := BuildGateway(func(g *gwapiv1.Gateway) {
+		g.Spec.Listeners = []gwapiv1.Listener{
+			{Name: "same", Port: 80, Protocol: gwapiv1.HTTPProtocolType},
+			{Name: "all", Port: 81, Protocol: gwapiv1.HTTPProtocolType, AllowedRoutes: allNamespaces},
+			{Name: "selector", Port: 82, Protocol: gwapiv1.HTTPProtocolType, AllowedRoutes: &gwapiv1.AllowedRoutes{
+				Namespaces: &gwapiv1.RouteNamespaces{
+					From:     ptr.To(gwapiv1.NamespacesFromSelector),
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+				},
+			}},
+			{Name: "grpc-only", Port: 83, Protocol: gwapiv1.HTTPProtocolType, AllowedRoutes: &gwapiv1.AllowedRoutes{
+				Namespaces: allNamespaces.Namespaces,
+				Kinds:      []gwapiv1.RouteGroupKind{{Kind: "GRPCRoute"}},
+			}},
+			{Name: "tcp", Port: 84, Protocol: gwapiv1.TCPProtocolType, AllowedRoutes: allNamespaces},
+		}
+	})
+
+	parentRef := func(sectionName string) []gwapiv1.ParentReference {
+		ref := gwapiv1.ParentReference{Name: "my-gateway", Namespace: ptr.To(gwapiv1.Namespace("my-namespace"))}
+		if sectionName != "" {
+			ref.SectionName = ptr.To(gwapiv1.SectionName(sectionName))
+		}
+		return []gwapiv1.ParentReference{ref}
+	}
+
+	namespaces := []*core.Namespace{
+		BuildNamespace(),
+		BuildNamespace(func(ns *core.Namespace) {
+			ns.Name = "prod"
+			ns.Labels = map[string]string{"env": "prod"}
+		}),
+		BuildNamespace(func(ns *core.Namespace) { ns.Name = "dev" }),
+	}
+	httpRoutes := []*gwapiv1.HTTPRoute{
+		BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) { r.Name = "http-same" }),
+		BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Name = "http-prod"
+			r.Namespace = "prod"
+			r.Spec.ParentRefs = parentRef("")
+		}),
+		BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Name = "http-dev"
+			r.Namespace = "dev"
+			r.Spec.ParentRefs = parentRef("")
+		}),
+		BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Name = "http-tcp"
+			r.Namespace = "dev"
+			r.Spec.ParentRefs = parentRef("tcp")
+		}),
+	}
+	grpcRoute := BuildGRPCRoute(func(r *gwapiv1.GRPCRoute) {
+		r.Name = "grpc-dev"
+		r.Namespace = "dev"
+		r.Spec.ParentRefs = parentRef("")
+	})
+	tcpRoute := BuildTCPRoute(func(r *gwapiv1.TCPRoute) {
+		r.Name = "tcp-dev"
+		r.Namespace = "dev"
+		r.Spec.ParentRefs = parentRef("")
+	})
+	udpRoute := BuildUDPRoute(func(r *gwapiv1.UDPRoute) {
+		r.Name = "udp-dev"
+		r.Namespace = "dev"
+		r.Spec.ParentRefs = parentRef("")
+	})
+
+	testCases := []struct {
+		name          string
+		options       []GatewayAPITopologyOptionsFunc
+		expectedLinks map[string][]string
+	}{
+		{
+			name: "not enforced",
+			expectedLinks: map[string][]string{
+				"my-gateway": {"http-same", "http-prod", "http-dev", "http-tcp", "grpc-dev", "tcp-dev", "udp-dev"},
+			},
+		},
+		{
+			name:    "enforced",
+			options: []GatewayAPITopologyOptionsFunc{EnforceAllowedRoutes()},
+			expectedLinks: map[string][]string{
+				"my-gateway": {"http-same", "http-prod", "http-dev", "grpc-dev", "tcp-dev"},
+			},
+		},
+		{
+			name:    "enforced with expanded listeners",
+			options: []GatewayAPITopologyOptionsFunc{EnforceAllowedRoutes(), ExpandGatewayListeners()},
+			expectedLinks: map[string][]string{
+				"my-gateway":           {"my-gateway#same", "my-gateway#all", "my-gateway#selector", "my-gateway#grpc-only", "my-gateway#tcp"},
+				"my-gateway#same":      {"http-same"},
+				"my-gateway#all":       {"http-same", "http-prod", "http-dev", "grpc-dev"},
+				"my-gateway#selector":  {"http-prod"},
+				"my-gateway#grpc-only": {"grpc-dev"},
+				"my-gateway#tcp":       {"tcp-dev"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			topology, err := NewGatewayAPITopology(append([]GatewayAPITopologyOptionsFunc{
+				WithNamespaces(namespaces...),
+				WithGateways(gateway),
+				WithHTTPRoutes(httpRoutes...),
+				WithGRPCRoutes(grpcRoute),
+				WithTCPRoutes(tcpRoute),
+				WithUDPRoutes(udpRoute),
+			}, tc.options...)...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			gw, found := lo.Find(topology.Targetables().Items(), func(t Targetable) bool {
+				return t.GetLocator() == "gateway.gateway.networking.k8s.io:my-namespace/my-gateway"
+			})
+			if !found {
+				t.Fatalf("expected gateway my-gateway in the topology")
+			}
+			links := make(map[string][]string)
+			linksFromTargetable(topology, gw, links)
+			for from, expectedTos := range tc.expectedLinks {
+				tos := links[from]
+				slices.Sort(expectedTos)
+				slices.Sort(tos)
+				if !slices.Equal(expectedTos, tos) {
+					t.Errorf("expected links from %s to be %v, got %v", from, expectedTos, tos)
+				}
+			}
+		})
+	}
+}
+
+func TestIntersectHostnames(t *testing.T) {
+	testCases := []struct {
+		name             string
+		listenerHostname *gwapiv1.Hostname
+		routeHostnames   []gwapiv1.Hostname
+		expected         []gwapiv1.Hostname
+		expectedOk       bool
+	}{
+		{
+			name:       "no hostnames",
+			expectedOk: true,
+		},
+		{
+			name:           "listener without hostname",
+			routeHostnames: []gwapiv1.Hostname{"foo.example.com", "*.example.org"},
+			expected:       []gwapiv1.Hostname{"foo.example.com", "*.example.org"},
+			expectedOk:     true,
+		},
+		{
+			name:             "route without hostnames",
+			listenerHostname: ptr.To(gwapiv1.Hostname("*.example.com")),
+			expected:         []gwapiv1.Hostname{"*.example.com"},
+			expectedOk:       true,
+		},
+		{
+			name:             "exact match",
+			listenerHostname: ptr.To(gwapiv1.Hostname("foo.example.com")),
+			routeHostnames:   []gwapiv1.Hostname{"foo.example.com", "bar.example.com"},
+			expected:         []gwapiv1.Hostname{"foo.example.com"},
+			expectedOk:       true,
+		},
+		{
+			name:             "route hostnames subsets of the listener wildcard hostname",
+			listenerHostname: ptr.To(gwapiv1.Hostname("*.example.com")),
+			routeHostnames:   []gwapiv1.Hostname{"foo.example.com", "bar.foo.example.com", "*.foo.example.com", "example.com", "foo.example.org"},
+			expected:         []gwapiv1.Hostname{"foo.example.com", "bar.foo.example.com", "*.foo.example.com"},
+			expectedOk:       true,
+		},
+		{
+			name:             "route wildcard hostname broader than the listener hostname",
+			listenerHostname: ptr.To(gwapiv1.Hostname("foo.example.com")),
+			routeHostnames:   []gwapiv1.Hostname{"*.example.com", "*.com"},
+			expected:         []gwapiv1.Hostname{"foo.example.com"},
+			expectedOk:       true,
+		},
+		{
+			name:             "no intersection",
+			listenerHostname: ptr.To(gwapiv1.Hostname("*.example.com")),
+			routeHostnames:   []gwapiv1.Hostname{"example.com", "foo.example.org", "*.org"},
+			expectedOk:       false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hostnames, ok := IntersectHostnames(tc.listenerHostname, tc.routeHostnames)
+			if ok != tc.expectedOk {
+				t.Errorf("expected intersection to be %v, got %v", tc.expectedOk, ok)
+			}
+			if !slices.Equal(hostnames, tc.expected) {
+				t.Errorf("expected hostnames %v, got %v", tc.expected, hostnames)
+			}
+		})
+	}
+}
+
+// TestGatewayAPITopologyWithHostnameIntersection tests for a topology of Gateway API resources where the links from
+// Gateways and Listeners to routes are restricted to the ones whose hostnames intersect.
+func TestGatewayAPITopologyWithHostnameIntersection(t *testing.T) {
+	gateway := BuildGateway(func(g *gwapiv1.Gateway) {
+		g.Spec.Listeners = []gwapiv1.Listener{
+			{Name: "any", Port: 80, Protocol: gwapiv1.HTTPProtocolType},
+			{Name: "wildcard", Port: 81, Protocol: gwapiv1.HTTPProtocolType, Hostname: ptr.To(gwapiv1.Hostname("*.example.com"))},
+			{Name: "exact", Port: 82, Protocol: gwapiv1.HTTPProtocolType, Hostname: ptr.To(gwapiv1.Hostname("foo.example.org"))},
+		}
+	})
+	httpRoutes := []*gwapiv1.HTTPRoute{
+		BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) { r.Name = "no-hostnames" }),
+		BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Name = "example-com"
+			r.Spec.Hostnames = []gwapiv1.Hostname{"foo.example.com"}
+		}),
+		BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Name = "example-org"
+			r.Spec.Hostnames = []gwapiv1.Hostname{"*.example.org"}
+		}),
+		BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Name = "example-net"
+			r.Spec.Hostnames = []gwapiv1.Hostname{"example.net"}
+			r.Spec.ParentRefs[0].SectionName = ptr.To(gwapiv1.SectionName("exact"))
+		}),
+	}
+
+	testCases := []struct {
+		name          string
+		options       []GatewayAPITopologyOptionsFunc
+		expectedLinks map[string][]string
+	}{
+		{
+			name:    "enforced",
+			options: []GatewayAPITopologyOptionsFunc{EnforceHostnameIntersection()},
+			expectedLinks: map[string][]string{
+				"my-gateway": {"no-hostnames", "example-com", "example-org"},
+			},
+		},
+		{
+			name:    "enforced with expanded listeners",
+			options: []GatewayAPITopologyOptionsFunc{EnforceHostnameIntersection(), ExpandGatewayListeners()},
+			expectedLinks: map[string][]string{
+				"my-gateway":          {"my-gateway#any", "my-gateway#wildcard", "my-gateway#exact"},
+				"my-gateway#any":      {"no-hostnames", "example-com", "example-org"},
+				"my-gateway#wildcard": {"no-hostnames", "example-com"},
+				"my-gateway#exact":    {"no-hostnames", "example-org"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			topology, err := NewGatewayAPITopology(append([]GatewayAPITopologyOptionsFunc{
+				WithGateways(gateway),
+				WithHTTPRoutes(httpRoutes...),
+			}, tc.options...)...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			gw, found := lo.Find(topology.Targetables().Items(), func(t Targetable) bool {
+				return t.GetLocator() == "gateway.gateway.networking.k8s.io:my-namespace/my-gateway"
+			})
+			if !found {
+				t.Fatalf("expected gateway my-gateway in the topology")
+			}
+			links := make(map[string][]string)
+			linksFromTargetable(topology, gw, links)
+			for from, expectedTos := range tc.expectedLinks {
+				tos := links[from]
+				slices.Sort(expectedTos)
+				slices.Sort(tos)
+				if !slices.Equal(expectedTos, tos) {
+					t.Errorf("expected links from %s to be %v, got %v", from, expectedTos, tos)
+				}
+			}
+		})
+	}
+
+	listener := &Listener{Listener: &gateway.Spec.Listeners[2], Gateway: &Gateway{Gateway: gateway}}
+	if hostnames, _ := EffectiveHostnames(listener, &HTTPRoute{HTTPRoute: httpRoutes[2]}); !slices.Equal(hostnames, []gwapiv1.Hostname{"foo.example.org"}) {
+		t.Errorf("expected effective hostnames of listener exact and route example-org to be [foo.example.org], got %v", hostnames)
+	}
+}
+
+// TestGatewayAPITopologyWithParentRefPorts tests for the links from Gateways and Listeners to routes whose parentRefs
+// specify a port, with and without a section name.
+func TestGatewayAPITopologyWithParentRefPorts(t *testing.T) {
+	gateway := BuildGateway(func(g *gwapiv1.Gateway) {
+		g.Spec.Listeners = []gwapiv1.Listener{
+			{Name: "http", Port: 80, Protocol: gwapiv1.HTTPProtocolType},
+			{Name: "http-alt", Port: 8080, Protocol: gwapiv1.HTTPProtocolType},
+			{Name: "http-alt-foo", Port: 8080, Protocol: gwapiv1.HTTPProtocolType, Hostname: ptr.To(gwapiv1.Hostname("foo.example.com"))},
+			{Name: "https", Port: 443, Protocol: gwapiv1.HTTPSProtocolType},
+		}
+	})
+
+	testCases := []struct {
+		name              string
+		sectionName       *gwapiv1.SectionName
+		port              *gwapiv1.PortNumber
+		expectedListeners []string
+	}{
+		{
+			name:              "neither section name nor port",
+			expectedListeners: []string{"my-gateway#http", "my-gateway#http-alt", "my-gateway#http-alt-foo", "my-gateway#https"},
+		},
+		{
+			name:              "section name only",
+			sectionName:       ptr.To(gwapiv1.SectionName("https")),
+			expectedListeners: []string{"my-gateway#https"},
+		},
+		{
+			name:              "port only",
+			port:              ptr.To(gwapiv1.PortNumber(8080)),
+			expectedListeners: []string{"my-gateway#http-alt", "my-gateway#http-alt-foo"},
+		},
+		{
+			name:              "section name and port of the same listener",
+			sectionName:       ptr.To(gwapiv1.SectionName("http-alt")),
+			port:              ptr.To(gwapiv1.PortNumber(8080)),
+			expectedListeners: []string{"my-gateway#http-alt"},
+		},
+		{
+			name:        "section name and port of different listeners",
+			sectionName: ptr.To(gwapiv1.SectionName("http")),
+			port:        ptr.To(gwapiv1.PortNumber(8080)),
+		},
+		{
+			name: "port without listener",
+			port: ptr.To(gwapiv1.PortNumber(9090)),
+		},
+		{
+			name:        "section name without listener",
+			sectionName: ptr.To(gwapiv1.SectionName("unknown")),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpRoute := BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+				r.Spec.ParentRefs[0].SectionName = tc.sectionName
+				r.Spec.ParentRefs[0].Port = tc.port
+			})
+
+			expandedTopology, err := NewGatewayAPITopology(WithGateways(gateway), WithHTTPRoutes(httpRoute), ExpandGatewayListeners())
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			route := expandedTopology.Targetables().Items(func(o Object) bool { return o.GroupVersionKind().GroupKind() == HTTPRouteGroupKind })[0]
+			listeners := lo.Map(expandedTopology.Targetables().Parents(route), func(parent Targetable, _ int) string { return parent.GetName() })
+			slices.Sort(listeners)
+			slices.Sort(tc.expectedListeners)
+			if !slices.Equal(listeners, tc.expectedListeners) {
+				t.Errorf("expected listeners %v, got %v", tc.expectedListeners, listeners)
+			}
+
+			topology, err := NewGatewayAPITopology(WithGateways(gateway), WithHTTPRoutes(httpRoute))
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			route = topology.Targetables().Items(func(o Object) bool { return o.GroupVersionKind().GroupKind() == HTTPRouteGroupKind })[0]
+			if gateways := topology.Targetables().Parents(route); len(gateways) != 1 {
+				t.Errorf("expected the route linked from the gateway by name, got %d gateways", len(gateways))
+			}
+		})
+	}
+}
+
+// TestGatewayAPITopologyWithUnknownParentRefSectionName tests that routes whose parentRefs specify a section name that
+// matches no listener are still linked from the Gateway when the listeners are not expanded.
+func TestGatewayAPITopologyWithUnknownParentRefSectionName(t *testing.T) {
+	httpRoute := BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+		r.Spec.ParentRefs[0].SectionName = ptr.To(gwapiv1.SectionName("unknown"))
+	})
+	tcpRoute := BuildTCPRoute(func(r *gwapiv1.TCPRoute) {
+		r.Spec.ParentRefs[0].SectionName = ptr.To(gwapiv1.SectionName("unknown"))
+	})
+
+	topology, err := NewGatewayAPITopology(
+		WithGateways(BuildGateway()),
+		WithHTTPRoutes(httpRoute),
+		WithTCPRoutes(tcpRoute),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	links := make(map[string][]string)
+	for _, root := range topology.Targetables().Roots() {
+		linksFromTargetable(topology, root, links)
+	}
+	expectedTos := []string{"my-http-route", "my-tcp-route"}
+	tos := links["my-gateway"]
+	slices.Sort(tos)
+	if !slices.Equal(expectedTos, tos) {
+		t.Errorf("expected links from my-gateway to be %v, got %v", expectedTos, tos)
+	}
+}
+
+// TestGatewayAPITopologyWithRouteParentStatus tests for a topology of Gateway API resources where the links from
+// Gateways and Listeners to routes are established based on the status of the routes.
+func TestGatewayAPITopologyWithRouteParentStatus(t *testing.T) {
+	gateway := BuildGateway(func(g *gwapiv1.Gateway) {
+		g.Spec.Listeners = []gwapiv1.Listener{
+			{Name: "a", Port: 80, Protocol: gwapiv1.HTTPProtocolType},
+			{Name: "b", Port: 81, Protocol: gwapiv1.HTTPProtocolType},
+		}
+	})
+
+	buildHTTPRoute := func(name string, sectionName, statusSectionName *gwapiv1.SectionName, controllerName gwapiv1.GatewayController, accepted metav1.ConditionStatus) *gwapiv1.HTTPRoute {
+		return BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Name = name
+			r.Spec.ParentRefs[0].SectionName = sectionName
+			if controllerName == "" {
+				return
+			}
+			r.Status.Parents = []gwapiv1.RouteParentStatus{
+				{
+					ParentRef:      gwapiv1.ParentReference{Name: "my-gateway", SectionName: statusSectionName},
+					ControllerName: controllerName,
+					Conditions:     []metav1.Condition{{Type: string(gwapiv1.RouteConditionAccepted), Status: accepted}},
+				},
+			}
+		})
+	}
+	sectionA := ptr.To(gwapiv1.SectionName("a"))
+	sectionB := ptr.To(gwapiv1.SectionName("b"))
+	httpRoutes := []*gwapiv1.HTTPRoute{
+		buildHTTPRoute("accepted", nil, nil, "my-gateway-controller", metav1.ConditionTrue),
+		buildHTTPRoute("accepted-section", sectionA, sectionA, "my-gateway-controller", metav1.ConditionTrue),
+		buildHTTPRoute("not-accepted", nil, nil, "my-gateway-controller", metav1.ConditionFalse),
+		buildHTTPRoute("other-controller", nil, nil, "other-controller", metav1.ConditionTrue),
+		buildHTTPRoute("stale", sectionA, sectionB, "my-gateway-controller", metav1.ConditionTrue),
+		buildHTTPRoute("no-status", nil, nil, "", ""),
+	}
+
+	testCases := []struct {
+		name          string
+		programmed    metav1.ConditionStatus
+		options       []GatewayAPITopologyOptionsFunc
+		expectedLinks map[string][]string
+	}{
+		{
+			name:    "controller of the gateway class",
+			options: []GatewayAPITopologyOptionsFunc{TrustRouteParentStatus()},
+			expectedLinks: map[string][]string{
+				"my-gateway": {"accepted", "accepted-section"},
+			},
+		},
+		{
+			name:    "controller of the gateway class with expanded listeners",
+			options: []GatewayAPITopologyOptionsFunc{TrustRouteParentStatus(), ExpandGatewayListeners()},
+			expectedLinks: map[string][]string{
+				"my-gateway#a": {"accepted", "accepted-section"},
+				"my-gateway#b": {"accepted"},
+			},
+		},
+		{
+			name:    "given controller names",
+			options: []GatewayAPITopologyOptionsFunc{TrustRouteParentStatus("other-controller")},
+			expectedLinks: map[string][]string{
+				"my-gateway": {"other-controller"},
+			},
+		},
+		{
+			name:       "skip unprogrammed gateways",
+			programmed: metav1.ConditionFalse,
+			options:    []GatewayAPITopologyOptionsFunc{SkipUnprogrammedGateways()},
+		},
+		{
+			name:       "programmed gateway",
+			programmed: metav1.ConditionTrue,
+			options:    []GatewayAPITopologyOptionsFunc{SkipUnprogrammedGateways()},
+			expectedLinks: map[string][]string{
+				"my-gateway": {"accepted", "accepted-section", "not-accepted", "other-controller", "stale", "no-status"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			gw := gateway.DeepCopy()
+			if tc.programmed != "" {
+				gw.Status.Conditions = []metav1.Condition{{Type: string(gwapiv1.GatewayConditionProgrammed), Status: tc.programmed}}
+			}
+			topology, err := NewGatewayAPITopology(append([]GatewayAPITopologyOptionsFunc{
+				WithGatewayClasses(BuildGatewayClass()),
+				WithGateways(gw),
+				WithHTTPRoutes(httpRoutes...),
+			}, tc.options...)...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			links := make(map[string][]string)
+			for _, root := range topology.Targetables().Roots() {
+				linksFromTargetable(topology, root, links)
+			}
+			if _, found := links["my-gateway"]; found != (tc.expectedLinks != nil) {
+				t.Errorf("expected gateway my-gateway in the topology to be %v, got %v", tc.expectedLinks != nil, found)
+			}
+			for from, expectedTos := range tc.expectedLinks {
+				tos := links[from]
+				slices.Sort(expectedTos)
+				slices.Sort(tos)
+				if !slices.Equal(expectedTos, tos) {
+					t.Errorf("expected links from %s to be %v, got %v", from, expectedTos, tos)
+				}
+			}
+		})
+	}
+}
+
+// TestGatewayAPITopologyWithUnprogrammedGateways tests for a topology of Gateway API resources that leaves out the
+// Gateways whose `Programmed` condition is false, and their ListenerSets.
+func TestGatewayAPITopologyWithUnprogrammedGateways(t *testing.T) {
+	gateways := []*gwapiv1.Gateway{
+		BuildGateway(),
+		BuildGateway(func(g *gwapiv1.Gateway) {
+			g.Name = "unprogrammed-gateway"
+			g.Status.Conditions = []metav1.Condition{{Type: string(gwapiv1.GatewayConditionProgrammed), Status: metav1.ConditionFalse}}
+		}),
+	}
+	listenerSets := []*gwapiv1.ListenerSet{
+		BuildListenerSet(),
+		BuildListenerSet(func(l *gwapiv1.ListenerSet) {
+			l.Name = "unprogrammed-listener-set"
+			l.Spec.ParentRef.Name = "unprogrammed-gateway"
+		}),
+	}
+	httpRoute := BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+		r.Spec.ParentRefs = []gwapiv1.ParentReference{
+			{Kind: ptr.To(gwapiv1.Kind("ListenerSet")), Name: "my-listener-set"},
+			{Kind: ptr.To(gwapiv1.Kind("ListenerSet")), Name: "unprogrammed-listener-set"},
+		}
+	})
+
+	for _, expandListeners := range []bool{false, true} {
+		t.Run(fmt.Sprintf("expand listeners %t", expandListeners), func(t *testing.T) {
+			options := []GatewayAPITopologyOptionsFunc{
+				WithGatewayClasses(BuildGatewayClass()),
+				WithGateways(gateways...),
+				WithListenerSets(listenerSets...),
+				WithHTTPRoutes(httpRoute),
+				SkipUnprogrammedGateways(),
+			}
+			if expandListeners {
+				options = append(options, ExpandGatewayListeners())
+			}
+			topology, err := NewGatewayAPITopology(options...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			names := lo.Map(topology.Targetables().Items(), func(t Targetable, _ int) string { return t.GetName() })
+			for _, name := range []string{"unprogrammed-gateway", "unprogrammed-listener-set", "unprogrammed-listener-set#my-listener-set-listener"} {
+				if lo.Contains(names, name) {
+					t.Errorf("expected %s to be left out of the topology", name)
+				}
+			}
+			route, _ := lo.Find(topology.Targetables().Items(), func(t Targetable) bool { return t.GetName() == "my-http-route" })
+			parents := lo.Map(topology.Targetables().Parents(route), func(t Targetable, _ int) string { return t.GetName() })
+			expectedParents := []string{"my-listener-set"}
+			if expandListeners {
+				expectedParents = []string{"my-listener-set#my-listener-set-listener"}
+			}
+			if !slices.Equal(parents, expectedParents) {
+				t.Errorf("expected parents of my-http-route to be %v, got %v", expectedParents, parents)
+			}
+		})
+	}
+}
+
+func TestGatewayAPITopologyWithGatewayControllerNames(t *testing.T) {
+	gatewayClasses := []*gwapiv1.GatewayClass{
+		BuildGatewayClass(),
+		BuildGatewayClass(func(gc *gwapiv1.GatewayClass) {
+			gc.Name = "other-gateway-class"
+			gc.Spec.ControllerName = "other-controller"
+		}),
+	}
+	gateways := []*gwapiv1.Gateway{
+		BuildGateway(),
+		BuildGateway(func(g *gwapiv1.Gateway) {
+			g.Name = "other-gateway"
+			g.Spec.GatewayClassName = "other-gateway-class"
+		}),
+	}
+	buildHTTPRoute := func(name string, parentRefs []string, backendRefs ...string) *gwapiv1.HTTPRoute {
+		return BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Name = name
+			r.Spec.ParentRefs = lo.Map(parentRefs, func(parentRef string, _ int) gwapiv1.ParentReference {
+				return gwapiv1.ParentReference{Name: gwapiv1.ObjectName(parentRef)}
+			})
+			r.Spec.Rules[0].BackendRefs = lo.Map(backendRefs, func(backendRef string, _ int) gwapiv1.HTTPBackendRef {
+				return BuildHTTPBackendRef(func(bor *gwapiv1.BackendObjectReference) { bor.Name = gwapiv1.ObjectName(backendRef) })
+			})
+		})
+	}
+	httpRoutes := []*gwapiv1.HTTPRoute{
+		buildHTTPRoute("my-route", []string{"my-gateway"}, "my-service", "shared-service"),
+		buildHTTPRoute("other-route", []string{"other-gateway"}, "other-service", "shared-service"),
+		buildHTTPRoute("both-gateways-route", []string{"other-gateway", "my-gateway"}, "both-gateways-service"),
+	}
+	services := lo.Map([]string{"my-service", "other-service", "shared-service", "both-gateways-service", "unreferenced-service"}, func(name string, _ int) *core.Service {
+		return BuildService(func(s *core.Service) { s.Name = name })
+	})
+
+	testCases := []struct {
+		name                string
+		controllerNames     []gwapiv1.GatewayController
+		expectedTargetables []string
+	}{
+		{
+			name:                "no controller names",
+			expectedTargetables: []string{"my-gateway-class", "other-gateway-class", "my-gateway", "other-gateway", "my-route", "other-route", "both-gateways-route", "my-service", "other-service", "shared-service", "both-gateways-service", "unreferenced-service"},
+		},
+		{
+			name:                "my controller",
+			controllerNames:     []gwapiv1.GatewayController{"my-gateway-controller"},
+			expectedTargetables: []string{"my-gateway-class", "my-gateway", "my-route", "both-gateways-route", "my-service", "shared-service", "both-gateways-service", "unreferenced-service"},
+		},
+		{
+			name:                "other controller",
+			controllerNames:     []gwapiv1.GatewayController{"other-controller"},
+			expectedTargetables: []string{"other-gateway-class", "other-gateway", "other-route", "both-gateways-route", "other-service", "shared-service", "both-gateways-service", "unreferenced-service"},
+		},
+		{
+			name:                "unknown controller",
+			controllerNames:     []gwapiv1.GatewayController{"unknown-controller"},
+			expectedTargetables: []string{"unreferenced-service"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := []GatewayAPITopologyOptionsFunc{
+				WithGatewayClasses(gatewayClasses...),
+				WithGateways(gateways...),
+				WithHTTPRoutes(httpRoutes...),
+				WithServices(services...),
+			}
+			if len(tc.controllerNames) > 0 {
+				opts = append(opts, WithGatewayControllerNames(tc.controllerNames...))
+			}
+			topology, err := NewGatewayAPITopology(opts...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			targetables := lo.Map(topology.Targetables().Items(), func(targetable Targetable, _ int) string {
+				return targetable.GetName()
+			})
+			slices.Sort(targetables)
+			slices.Sort(tc.expectedTargetables)
+			if !slices.Equal(tc.expectedTargetables, targetables) {
+				t.Errorf("expected targetables %v, got %v", tc.expectedTargetables, targetables)
+			}
+		})
+	}
+}
+
+// TestGatewayAPITopologyWithListenerSets tests for a topology of Gateway API resources where routes attach to the
+// Listeners defined in a ListenerSet of a Gateway.
+func TestGatewayAPITopologyWithListenerSets(t *testing.T) {
+	listenerSet := BuildListenerSet(func(l *gwapiv1.ListenerSet) {
+		l.Spec.Listeners = []gwapiv1.ListenerEntry{
+			{Name: "a", Port: 8080, Protocol: gwapiv1.HTTPProtocolType},
+			{Name: "b", Port: 8081, Protocol: gwapiv1.HTTPProtocolType},
+		}
+	})
+	buildHTTPRoute := func(name string, kind gwapiv1.Kind, parentName gwapiv1.ObjectName, sectionName *gwapiv1.SectionName) *gwapiv1.HTTPRoute {
+		return BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Name = name
+			r.Spec.ParentRefs[0].Kind = ptr.To(kind)
+			r.Spec.ParentRefs[0].Name = parentName
+			r.Spec.ParentRefs[0].SectionName = sectionName
+		})
+	}
+	httpRoutes := []*gwapiv1.HTTPRoute{
+		buildHTTPRoute("gateway-route", "Gateway", "my-gateway", nil),
+		buildHTTPRoute("listener-set-route", "ListenerSet", "my-listener-set", nil),
+		buildHTTPRoute("listener-set-section-route", "ListenerSet", "my-listener-set", ptr.To(gwapiv1.SectionName("a"))),
+		buildHTTPRoute("unknown-section-route", "ListenerSet", "my-listener-set", ptr.To(gwapiv1.SectionName("c"))),
+		// a route that refers to a Gateway with the name of the ListenerSet
+		buildHTTPRoute("ambiguous-route", "Gateway", "my-listener-set", nil),
+	}
+
+	testCases := []struct {
+		name          string
+		options       []GatewayAPITopologyOptionsFunc
+		expectedLinks map[string][]string
+	}{
+		{
+			name: "without expanding listeners",
+			expectedLinks: map[string][]string{
+				"my-gateway-class": {"my-gateway"},
+				"my-gateway":       {"gateway-route", "my-listener-set"},
+				"my-listener-set":  {"listener-set-route", "listener-set-section-route"},
+			},
+		},
+		{
+			name:    "expanding listeners",
+			options: []GatewayAPITopologyOptionsFunc{ExpandGatewayListeners()},
+			expectedLinks: map[string][]string{
+				"my-gateway-class":       {"my-gateway"},
+				"my-gateway":             {"my-gateway#my-listener", "my-listener-set"},
+				"my-gateway#my-listener": {"gateway-route"},
+				"my-listener-set":        {"my-listener-set#a", "my-listener-set#b"},
+				"my-listener-set#a":      {"listener-set-route", "listener-set-section-route"},
+				"my-listener-set#b":      {"listener-set-route"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			listenerSetPolicy := buildPolicy(func(p *TestPolicy) {
+				p.Name = "listener-set-policy"
+				p.Spec.TargetRef.Group = gwapiv1.GroupName
+				p.Spec.TargetRef.Kind = "ListenerSet"
+				p.Spec.TargetRef.Name = "my-listener-set"
+			})
+			topology, err := NewGatewayAPITopology(append([]GatewayAPITopologyOptionsFunc{
+				WithGatewayClasses(BuildGatewayClass()),
+				WithGateways(BuildGateway()),
+				WithListenerSets(listenerSet),
+				WithHTTPRoutes(httpRoutes...),
+				WithGatewayAPITopologyPolicies(listenerSetPolicy),
+			}, tc.options...)...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			links := make(map[string][]string)
+			for _, root := range topology.Targetables().Roots() {
+				linksFromTargetable(topology, root, links)
+			}
+			for from, expectedTos := range tc.expectedLinks {
+				tos := links[from]
+				slices.Sort(expectedTos)
+				slices.Sort(tos)
+				if !slices.Equal(expectedTos, tos) {
+					t.Errorf("expected links from %s to be %v, got %v", from, expectedTos, tos)
+				}
+			}
+			targetables := lo.SliceToMap(topology.Targetables().Items(), func(t Targetable) (string, Targetable) {
+				return t.GetName(), t
+			})
+			for _, route := range []string{"unknown-section-route", "ambiguous-route"} {
+				if parents := topology.Targetables().Parents(targetables[route]); len(parents) > 0 {
+					t.Errorf("expected route %s not to be linked, got parents %v", route, lo.Map(parents, func(p Targetable, _ int) string { return p.GetName() }))
+				}
+			}
+			// the policies attached to the ListenerSet apply to the routes attached to its Listeners
+			paths := topology.Targetables().Paths(targetables["my-gateway"], targetables["listener-set-section-route"])
+			if len(paths) == 0 {
+				t.Errorf("expected paths from my-gateway to listener-set-section-route")
+			}
+			for _, path := range paths {
+				if !lo.ContainsBy(path, func(t Targetable) bool { return t.GetName() == "my-listener-set" }) {
+					t.Errorf("expected path from my-gateway to listener-set-section-route through my-listener-set, got %v", lo.Map(path, func(t Targetable, _ int) string { return t.GetName() }))
+				}
+			}
+
+			targetable, found := lo.Find(topology.Targetables().Items(), func(t Targetable) bool {
+				return t.GetLocator() == "listenerset.gateway.networking.k8s.io:my-namespace/my-listener-set"
+			})
+			if !found {
+				t.Fatalf("expected listener set my-listener-set in the topology")
+			}
+			if policies := targetable.Policies(); len(policies) != 1 || policies[0].GetLocator() != listenerSetPolicy.GetLocator() {
+				t.Errorf("expected policy %s attached to listener set my-listener-set, got %v", listenerSetPolicy.GetLocator(), policies)
+			}
+		})
+	}
+}
+
+// TestGatewayAPITopologyWithOrphanListenerSets tests for a topology of Gateway API resources with a ListenerSet whose
+// parent Gateway is missing, which is left out of the topology along with its listeners.
+func TestGatewayAPITopologyWithOrphanListenerSets(t *testing.T) {
+	listenerSet := BuildListenerSet(func(l *gwapiv1.ListenerSet) {
+		l.Spec.ParentRef.Name = "missing-gateway"
+	})
+	httpRoute := BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+		r.Spec.ParentRefs[0].Kind = ptr.To(gwapiv1.Kind("ListenerSet"))
+		r.Spec.ParentRefs[0].Name = "my-listener-set"
+	})
+
+	testCases := []struct {
+		name          string
+		options       []GatewayAPITopologyOptionsFunc
+		expectedLinks map[string][]string
+	}{
+		{
+			name: "without expanding listeners",
+			expectedLinks: map[string][]string{
+				"my-gateway-class": {"my-gateway"},
+				"my-gateway":       {},
+			},
+		},
+		{
+			name:    "expanding listeners",
+			options: []GatewayAPITopologyOptionsFunc{ExpandGatewayListeners()},
+			expectedLinks: map[string][]string{
+				"my-gateway": {"my-gateway#my-listener"},
+			},
+		},
+		{
+			name:    "expanding hostnames",
+			options: []GatewayAPITopologyOptionsFunc{ExpandHostnames()},
+			expectedLinks: map[string][]string{
+				"my-gateway": {"my-gateway#my-listener"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			topology, err := NewGatewayAPITopology(append([]GatewayAPITopologyOptionsFunc{
+				WithGatewayClasses(BuildGatewayClass()),
+				WithGateways(BuildGateway()),
+				WithListenerSets(listenerSet),
+				WithHTTPRoutes(httpRoute),
+			}, tc.options...)...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			links := make(map[string][]string)
+			for _, root := range topology.Targetables().Roots() {
+				linksFromTargetable(topology, root, links)
+			}
+			for from, expectedTos := range tc.expectedLinks {
+				tos := links[from]
+				slices.Sort(expectedTos)
+				slices.Sort(tos)
+				if !slices.Equal(expectedTos, tos) {
+					t.Errorf("expected links from %s to be %v, got %v", from, expectedTos, tos)
+				}
+			}
+			if lo.ContainsBy(topology.Targetables().Items(), func(t Targetable) bool {
+				_, ok := t.(*ListenerSet)
+				return ok
+			}) {
+				t.Errorf("expected no orphan listener set in the topology")
+			}
+			if lo.ContainsBy(topology.Targetables().Items(), func(t Targetable) bool {
+				listener, ok := t.(*Listener)
+				return ok && listener.ListenerSet != nil
+			}) {
+				t.Errorf("expected no listeners of the orphan listener set in the topology")
+			}
+			route, _ := lo.Find(topology.Targetables().Items(), func(t Targetable) bool { return t.GetName() == "my-http-route" })
+			if parents := topology.Targetables().Parents(route); len(parents) != 0 {
+				t.Errorf("expected no parents of my-http-route, got %d", len(parents))
+			}
+		})
+	}
+}
+
+// TestGatewayAPITopologyWithBackendKinds tests for a topology of Gateway API resources where routes refer to backends
+// of a kind other than core Services, registered as a backend kind.
+func TestGatewayAPITopologyWithBackendKinds(t *testing.T) {
+	serviceImportBackendRef := func(name gwapiv1.ObjectName, port *gwapiv1.PortNumber) gwapiv1.HTTPBackendRef {
+		return BuildHTTPBackendRef(func(backendRef *gwapiv1.BackendObjectReference) {
+			backendRef.Group = ptr.To(gwapiv1.Group(ServiceImportGroupKind.Group))
+			backendRef.Kind = ptr.To(gwapiv1.Kind(ServiceImportGroupKind.Kind))
+			backendRef.Name = name
+			backendRef.Port = port
+		})
+	}
+	httpRoute := BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+		r.Spec.Rules[0].BackendRefs = []gwapiv1.HTTPBackendRef{
+			BuildHTTPBackendRef(),
+			serviceImportBackendRef("my-service-import", nil),
+			serviceImportBackendRef("my-service-import", ptr.To(gwapiv1.PortNumber(80))),
+			serviceImportBackendRef("unknown-service-import", nil),
+		}
+	})
+	// a service import with the name of a service must not be mistaken for the service
+	serviceImports := []*ServiceImport{
+		BuildServiceImport(),
+		BuildServiceImport(func(s *ServiceImport) { s.Name = "my-service" }),
+	}
+
+	testCases := []struct {
+		name          string
+		expandPorts   bool
+		options       []GatewayAPITopologyOptionsFunc
+		expectedLinks map[string][]string
+	}{
+		{
+			name: "routes to backends",
+			expectedLinks: map[string][]string{
+				"my-http-route": {"my-service", "my-service-import"},
+			},
+		},
+		{
+			name:        "routes to backend ports",
+			expandPorts: true,
+			expectedLinks: map[string][]string{
+				"my-http-route":     {"my-service", "my-service-import", "my-service-import#http"},
+				"my-service-import": {"my-service-import#http"},
+			},
+		},
+		{
+			name:        "route rules to backend ports",
+			expandPorts: true,
+			options:     []GatewayAPITopologyOptionsFunc{ExpandHTTPRouteRules()},
+			expectedLinks: map[string][]string{
+				"my-http-route":        {"my-http-route#rule-1"},
+				"my-http-route#rule-1": {"my-service", "my-service-import", "my-service-import#http"},
+				"my-service-import":    {"my-service-import#http"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			topology, err := NewGatewayAPITopology(append([]GatewayAPITopologyOptionsFunc{
+				WithHTTPRoutes(httpRoute),
+				WithServices(BuildService()),
+				WithBackendKinds(ServiceImportBackendKind(tc.expandPorts, serviceImports...)),
+			}, tc.options...)...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			links := make(map[string][]string)
+			for _, root := range topology.Targetables().Roots() {
+				linksFromTargetable(topology, root, links)
+			}
+			for from, expectedTos := range tc.expectedLinks {
+				tos := links[from]
+				slices.Sort(expectedTos)
+				slices.Sort(tos)
+				if !slices.Equal(expectedTos, tos) {
+					t.Errorf("expected links from %s to be %v, got %v", from, expectedTos, tos)
+				}
+			}
+			if parents := topology.Targetables().Parents(serviceImports[1]); len(parents) > 0 {
+				t.Errorf("expected service import %s not to be linked, got parents %v", serviceImports[1].GetLocator(), lo.Map(parents, func(p Targetable, _ int) string { return p.GetLocator() }))
+			}
+		})
+	}
+}
+
+// TestGatewayAPITopologyWithMeshRoutes tests for a topology of Gateway API resources where routes attach to Services,
+// as in the Gateway API mesh (GAMMA) model.
+func TestGatewayAPITopologyWithMeshRoutes(t *testing.T) {
+	buildHTTPRoute := func(name string, port *gwapiv1.PortNumber, sectionName *gwapiv1.SectionName) *gwapiv1.HTTPRoute {
+		return BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Name = name
+			r.Spec.ParentRefs = []gwapiv1.ParentReference{
+				{
+					Group:       ptr.To(gwapiv1.Group("")),
+					Kind:        ptr.To(gwapiv1.Kind("Service")),
+					Name:        "my-service",
+					Port:        port,
+					SectionName: sectionName,
+				},
+			}
+			r.Spec.Rules[0].BackendRefs[0].Name = "my-service-v1"
+		})
+	}
+	httpRoutes := []*gwapiv1.HTTPRoute{
+		buildHTTPRoute("mesh-route", nil, nil),
+		buildHTTPRoute("mesh-port-route", ptr.To(gwapiv1.PortNumber(80)), nil),
+		buildHTTPRoute("mesh-section-route", nil, ptr.To(gwapiv1.SectionName("http"))),
+		BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Name = "gateway-route"
+			r.Spec.Rules[0].BackendRefs[0].Name = "my-service-v1"
+		}),
+	}
+	grpcRoute := BuildGRPCRoute(func(r *gwapiv1.GRPCRoute) {
+		r.Spec.ParentRefs = []gwapiv1.ParentReference{
+			{Group: ptr.To(gwapiv1.Group("")), Kind: ptr.To(gwapiv1.Kind("Service")), Name: "my-service"},
+		}
+		r.Spec.Rules[0].BackendRefs[0].Name = "my-service-v1"
+	})
+	services := []*core.Service{
+		BuildService(),
+		BuildService(func(s *core.Service) { s.Name = "my-service-v1" }),
+	}
+
+	testCases := []struct {
+		name          string
+		options       []GatewayAPITopologyOptionsFunc
+		expectedLinks map[string][]string
+	}{
+		{
+			name: "mesh routes disabled",
+			expectedLinks: map[string][]string{
+				"my-gateway": {"gateway-route"},
+				"my-service": {},
+			},
+		},
+		{
+			name:    "mesh routes",
+			options: []GatewayAPITopologyOptionsFunc{EnableMeshRoutes()},
+			expectedLinks: map[string][]string{
+				"my-gateway": {"gateway-route"},
+				"my-service": {"mesh-route", "mesh-port-route", "mesh-section-route", "my-grpc-route"},
+			},
+		},
+		{
+			name:    "mesh routes with service ports",
+			options: []GatewayAPITopologyOptionsFunc{EnableMeshRoutes(), ExpandServicePorts()},
+			expectedLinks: map[string][]string{
+				"my-gateway":      {"gateway-route"},
+				"my-service":      {"my-service#http", "mesh-route", "my-grpc-route"},
+				"my-service#http": {"mesh-port-route", "mesh-section-route"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			topology, err := NewGatewayAPITopology(append([]GatewayAPITopologyOptionsFunc{
+				WithGatewayClasses(BuildGatewayClass()),
+				WithGateways(BuildGateway()),
+				WithHTTPRoutes(httpRoutes...),
+				WithGRPCRoutes(grpcRoute),
+				WithServices(services...),
+			}, tc.options...)...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			links := make(map[string][]string)
+			for _, root := range topology.Targetables().Roots() {
+				linksFromTargetable(topology, root, links)
+			}
+			for from, expectedTos := range tc.expectedLinks {
+				tos := links[from]
+				slices.Sort(expectedTos)
+				slices.Sort(tos)
+				if !slices.Equal(expectedTos, tos) {
+					t.Errorf("expected links from %s to be %v, got %v", from, expectedTos, tos)
+				}
+			}
+		})
+	}
+
+	t.Run("routes to the parent service", func(t *testing.T) {
+		for _, options := range [][]GatewayAPITopologyOptionsFunc{
+			{EnableMeshRoutes()},
+			{EnableMeshRoutes(), ExpandServicePorts()},
+			{EnableMeshRoutes(), ExpandHTTPRouteRules()},
+			{EnableMeshRoutes(), ExpandHTTPRouteRules(), ExpandServicePorts()},
+		} {
+			topology, err := NewGatewayAPITopology(append([]GatewayAPITopologyOptionsFunc{
+				WithHTTPRoutes(BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+					r.Spec.ParentRefs = []gwapiv1.ParentReference{
+						{Group: ptr.To(gwapiv1.Group("")), Kind: ptr.To(gwapiv1.Kind("Service")), Name: "my-service"},
+					}
+					r.Spec.Rules[0].BackendRefs[0].Name = "my-service"
+				})),
+				WithServices(BuildService()),
+			}, options...)...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			service, ok := lo.Find(topology.Targetables().Items(), func(item Targetable) bool {
+				return item.GetName() == "my-service"
+			})
+			if !ok {
+				t.Fatalf("expected my-service in the topology")
+			}
+			if children := topology.Targetables().Children(service); !lo.ContainsBy(children, func(child Targetable) bool {
+				return child.GetName() == "my-http-route"
+			}) {
+				t.Errorf("expected my-http-route to be linked from my-service, got %v", lo.Map(children, func(child Targetable, _ int) string { return child.GetName() }))
+			}
+		}
+	})
+}
+
+func TestGatewayAPITopologyWithHostnames(t *testing.T) {
+	gateways := []*gwapiv1.Gateway{
+		BuildGateway(func(g *gwapiv1.Gateway) {
+			g.Spec.Listeners = append(g.Spec.Listeners, gwapiv1.Listener{Name: "wildcard-listener", Port: 8080, Protocol: "HTTP", Hostname: ptr.To(gwapiv1.Hostname("*.example.com"))})
+		}),
+		BuildGateway(func(g *gwapiv1.Gateway) {
+			g.Name = "other-gateway"
+			g.Spec.Listeners[0].Name = "other-listener"
+			g.Spec.Listeners[0].Hostname = ptr.To(gwapiv1.Hostname("api.example.com"))
+		}),
+	}
+	httpRoutes := []*gwapiv1.HTTPRoute{
+		BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Spec.Hostnames = []gwapiv1.Hostname{"api.example.com", "other.org"}
+			r.Spec.ParentRefs = append(r.Spec.ParentRefs, gwapiv1.ParentReference{Name: "other-gateway"})
+		}),
+		BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Name = "catch-all-route"
+			r.Spec.ParentRefs[0].SectionName = ptr.To(gwapiv1.SectionName("my-listener"))
+		}),
+		BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Name = "wildcard-route"
+			r.Spec.ParentRefs[0].SectionName = ptr.To(gwapiv1.SectionName("wildcard-listener"))
+		}),
+	}
+	hostnamePolicy := buildPolicy(func(p *TestPolicy) {
+		p.Spec.TargetRef.Group = gwapiv1.GroupName
+		p.Spec.TargetRef.Kind = "Gateway"
+		p.Spec.TargetRef.Name = "other-gateway"
+		p.Spec.TargetRef.SectionName = ptr.To(gwapiv1.SectionName("other-listener.api.example.com"))
+	})
+
+	topology, err := NewGatewayAPITopology(
+		WithGateways(gateways...),
+		WithHTTPRoutes(httpRoutes...),
+		WithGatewayAPITopologyPolicies(hostnamePolicy),
+		ExpandHostnames(),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	links := make(map[string][]string)
+	for _, root := range topology.Targetables().Roots() {
+		linksFromTargetable(topology, root, links)
+	}
+	expectedLinks := map[string][]string{
+		"my-gateway":                                        {"my-gateway#my-listener", "my-gateway#wildcard-listener"},
+		"my-gateway#my-listener":                            {"my-gateway#my-listener.api.example.com", "my-gateway#my-listener.other.org", "catch-all-route"},
+		"my-gateway#wildcard-listener":                      {"my-gateway#wildcard-listener.wildcard.example.com", "my-gateway#wildcard-listener.api.example.com"},
+		"other-gateway#other-listener":                      {"other-gateway#other-listener.api.example.com"},
+		"my-gateway#my-listener.api.example.com":            {"my-http-route"},
+		"my-gateway#my-listener.other.org":                  {"my-http-route"},
+		"my-gateway#wildcard-listener.api.example.com":      {"my-http-route"},
+		"my-gateway#wildcard-listener.wildcard.example.com": {"wildcard-route"},
+		"other-gateway#other-listener.api.example.com":      {"my-http-route"},
+	}
+	for from, expectedTos := range expectedLinks {
+		tos := links[from]
+		slices.Sort(expectedTos)
+		slices.Sort(tos)
+		if !slices.Equal(expectedTos, tos) {
+			t.Errorf("expected links from %s to be %v, got %v", from, expectedTos, tos)
+		}
+	}
+
+	hostname, found := lo.Find(topology.Targetables().Items(), func(targetable Targetable) bool {
+		return targetable.GetLocator() == "gateway.gateway.networking.k8s.io:my-namespace/other-gateway#other-listener.api.example.com"
+	})
+	if !found {
+		t.Fatalf("expected hostname other-gateway#other-listener.api.example.com in the topology")
+	}
+	if policies := hostname.Policies(); len(policies) != 1 || policies[0].GetName() != hostnamePolicy.GetName() {
+		t.Errorf("expected the policy attached to hostname other-gateway#other-listener.api.example.com, got %v", policies)
+	}
+
+	otherGateway, _ := lo.Find(topology.Targetables().Items(), func(targetable Targetable) bool { return targetable.GetName() == "other-gateway" })
+	route, _ := lo.Find(topology.Targetables().Items(), func(targetable Targetable) bool { return targetable.GetName() == "my-http-route" })
+	paths := topology.Targetables().Paths(otherGateway, route)
+	if len(paths) != 1 || !lo.ContainsBy(paths[0], func(targetable Targetable) bool { return targetable == hostname }) {
+		t.Errorf("expected a single path from other-gateway to my-http-route through hostname other-gateway#other-listener.api.example.com, got %v", paths)
+	}
+}
+
+// TestGatewayAPITopologyHostnameSectionNames tests that the section names of the hostnames of a topology pass the
+// validation of the `sectionName` field of a Gateway API policy target reference, so policies can target them.
+func TestGatewayAPITopologyHostnameSectionNames(t *testing.T) {
+	sectionNamePattern := regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+
+	gateway := BuildGateway(func(g *gwapiv1.Gateway) {
+		g.Spec.Listeners[0].Hostname = ptr.To(gwapiv1.Hostname("api.example.com"))
+		g.Spec.Listeners = append(g.Spec.Listeners, gwapiv1.Listener{Name: "wildcard-listener", Port: 8080, Protocol: "HTTP", Hostname: ptr.To(gwapiv1.Hostname("*.example.com"))})
+	})
+	wildcardPolicy := buildPolicy(func(p *TestPolicy) {
+		p.Spec.TargetRef.Group = gwapiv1.GroupName
+		p.Spec.TargetRef.Kind = "Gateway"
+		p.Spec.TargetRef.Name = "my-gateway"
+		p.Spec.TargetRef.SectionName = ptr.To(gwapiv1.SectionName("wildcard-listener.wildcard.example.com"))
+	})
+
+	topology, err := NewGatewayAPITopology(
+		WithGateways(gateway),
+		WithGatewayAPITopologyPolicies(wildcardPolicy),
+		ExpandHostnames(),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	hostnames := lo.FilterMap(topology.Targetables().Items(), func(targetable Targetable, _ int) (*Hostname, bool) {
+		hostname, ok := targetable.(*Hostname)
+		return hostname, ok
+	})
+	if len(hostnames) != 2 {
+		t.Fatalf("expected 2 hostnames in the topology, got %d", len(hostnames))
+	}
+	for _, hostname := range hostnames {
+		if sectionName := string(hostname.SectionName()); !sectionNamePattern.MatchString(sectionName) || len(sectionName) > 253 {
+			t.Errorf("expected the section name of hostname %s to be a valid section name, got %q", hostname.Hostname, sectionName)
+		}
+		expectedPolicies := 0
+		if hostname.Hostname == "*.example.com" {
+			expectedPolicies = 1
+		}
+		if policies := hostname.Policies(); len(policies) != expectedPolicies {
+			t.Errorf("expected %d policies attached to hostname %s, got %d", expectedPolicies, hostname.Hostname, len(policies))
+		}
+	}
+}
+
+// TestGatewayAPITopologyWithHostnamesOfGatewaysInTheSameNamespace tests for a topology of Gateway API resources with
+// expanded hostnames, where Gateways of the same namespace serve the same hostname, but a route attaches to only one of
+// them.
+func TestGatewayAPITopologyWithHostnamesOfGatewaysInTheSameNamespace(t *testing.T) {
+	gateways := []*gwapiv1.Gateway{
+		BuildGateway(func(g *gwapiv1.Gateway) {
+			g.Spec.Listeners[0].Hostname = ptr.To(gwapiv1.Hostname("api.example.com"))
+		}),
+		BuildGateway(func(g *gwapiv1.Gateway) {
+			g.Name = "other-gateway"
+			g.Spec.Listeners[0].Hostname = ptr.To(gwapiv1.Hostname("api.example.com"))
+		}),
+	}
+
+	topology, err := NewGatewayAPITopology(
+		WithGateways(gateways...),
+		WithHTTPRoutes(BuildHTTPRoute()),
+		ExpandHostnames(),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	links := make(map[string][]string)
+	for _, root := range topology.Targetables().Roots() {
+		linksFromTargetable(topology, root, links)
+	}
+	expectedLinks := map[string][]string{
+		"my-gateway#my-listener":                    {"my-gateway#my-listener.api.example.com"},
+		"other-gateway#my-listener":                 {"other-gateway#my-listener.api.example.com"},
+		"my-gateway#my-listener.api.example.com":    {"my-http-route"},
+		"other-gateway#my-listener.api.example.com": {},
+	}
+	for from, expectedTos := range expectedLinks {
+		tos := links[from]
+		slices.Sort(expectedTos)
+		slices.Sort(tos)
+		if !slices.Equal(expectedTos, tos) {
+			t.Errorf("expected links from %s to be %v, got %v", from, expectedTos, tos)
+		}
+	}
+
+	targetables := lo.SliceToMap(topology.Targetables().Items(), func(t Targetable) (string, Targetable) {
+		return t.GetName(), t
+	})
+	if paths := topology.Targetables().Paths(targetables["other-gateway"], targetables["my-http-route"]); len(paths) > 0 {
+		t.Errorf("expected no paths from other-gateway to my-http-route, got %v", paths)
+	}
+	if paths := topology.Targetables().Paths(targetables["my-gateway"], targetables["my-http-route"]); len(paths) != 1 {
+		t.Errorf("expected a single path from my-gateway to my-http-route, got %v", paths)
+	}
+}
+
+func TestGatewayAPITopologyWithEndpointSlices(t *testing.T) {
+	services := []*core.Service{
+		BuildService(func(s *core.Service) {
+			s.Spec.Ports = append(s.Spec.Ports, core.ServicePort{Name: "metrics", Port: 9090})
+		}),
+		BuildService(func(s *core.Service) { s.Name = "other-service" }),
+	}
+	endpointSlices := []*discovery.EndpointSlice{
+		BuildEndpointSlice(),
+		BuildEndpointSlice(func(e *discovery.EndpointSlice) {
+			e.Name = "my-service-def34"
+			e.Ports = append(e.Ports, discovery.EndpointPort{Name: ptr.To("metrics"), Port: ptr.To(int32(9090))})
+			e.Endpoints = append(e.Endpoints, discovery.Endpoint{Addresses: []string{"10.0.0.3"}, Zone: ptr.To("zone-a")}, discovery.Endpoint{Addresses: []string{"10.0.0.4"}})
+		}),
+		BuildEndpointSlice(func(e *discovery.EndpointSlice) {
+			e.Name = "my-service-ghi56"
+			e.Ports = nil
+		}),
+		BuildEndpointSlice(func(e *discovery.EndpointSlice) {
+			e.Name = "other-namespace-slice"
+			e.Namespace = "other-namespace"
+		}),
+		BuildEndpointSlice(func(e *discovery.EndpointSlice) {
+			e.Name = "unlabelled-slice"
+			e.Labels = nil
+		}),
+	}
+
+	testCases := []struct {
+		name          string
+		options       []GatewayAPITopologyOptionsFunc
+		expectedLinks map[string][]string
+	}{
+		{
+			name: "endpoint slices",
+			expectedLinks: map[string][]string{
+				"my-service":    {"my-service-abc12", "my-service-def34", "my-service-ghi56"},
+				"other-service": {},
+			},
+		},
+		{
+			name:    "endpoint slices with service ports",
+			options: []GatewayAPITopologyOptionsFunc{ExpandServicePorts()},
+			expectedLinks: map[string][]string{
+				"my-service":         {"my-service#http", "my-service#metrics", "my-service-ghi56"},
+				"my-service#http":    {"my-service-abc12", "my-service-def34"},
+				"my-service#metrics": {"my-service-def34"},
+			},
+		},
+		{
+			name:    "endpoint zones",
+			options: []GatewayAPITopologyOptionsFunc{ExpandEndpointZones()},
+			expectedLinks: map[string][]string{
+				"my-service":       {"my-service-abc12", "my-service-def34", "my-service-ghi56"},
+				"my-service-abc12": {"my-service-abc12#zone-a", "my-service-abc12#zone-b"},
+				"my-service-def34": {"my-service-def34#zone-a", "my-service-def34#zone-b"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			topology, err := NewGatewayAPITopology(append([]GatewayAPITopologyOptionsFunc{
+				WithServices(services...),
+				WithEndpointSlices(endpointSlices...),
+			}, tc.options...)...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			links := make(map[string][]string)
+			for _, root := range topology.Targetables().Roots() {
+				linksFromTargetable(topology, root, links)
+			}
+			for from, expectedTos := range tc.expectedLinks {
+				tos := links[from]
+				slices.Sort(expectedTos)
+				slices.Sort(tos)
+				if !slices.Equal(expectedTos, tos) {
+					t.Errorf("expected links from %s to be %v, got %v", from, expectedTos, tos)
+				}
+			}
+		})
+	}
+
+	t.Run("endpoints grouped by zone", func(t *testing.T) {
+		endpointZones := EndpointZonesFromEndpointSliceFunc(&EndpointSlice{EndpointSlice: endpointSlices[1]}, 0)
+		zones := lo.Map(endpointZones, func(endpointZone *EndpointZone, _ int) string { return endpointZone.Zone })
+		if !slices.Equal(zones, []string{"zone-a", "zone-b"}) {
+			t.Fatalf("expected zones [zone-a zone-b], got %v", zones)
+		}
+		if len(endpointZones[0].Endpoints) != 2 || len(endpointZones[1].Endpoints) != 1 {
+			t.Errorf("expected 2 endpoints in zone-a and 1 in zone-b, got %d and %d", len(endpointZones[0].Endpoints), len(endpointZones[1].Endpoints))
+		}
+		if gvk := endpointZones[0].GroupVersionKind(); gvk != discovery.SchemeGroupVersion.WithKind("EndpointZone") {
+			t.Errorf("expected the group version kind of the endpoint zones to be discovery.k8s.io/v1 EndpointZone, got %s", gvk)
+		}
+	})
+}
+
+func TestGatewayAPITopologyWithAllowedTargetKinds(t *testing.T) {
+	buildPolicy := func(name string, targetKinds, sectionKinds []schema.GroupKind, targetRef gwapiv1.LocalPolicyTargetReferenceWithSectionName) Policy {
+		policy := buildPolicy(func(p *TestPolicy) {
+			p.Name = name
+			p.Spec.TargetRef = targetRef
+		})
+		if targetKinds == nil && sectionKinds == nil {
+			return policy
+		}
+		return &allowedTargetsTestPolicy{TestPolicy: policy, targetKinds: targetKinds, sectionKinds: sectionKinds}
+	}
+	targetRef := func(kind gwapiv1.Kind, name gwapiv1.ObjectName, sectionName gwapiv1.SectionName) gwapiv1.LocalPolicyTargetReferenceWithSectionName {
+		ref := gwapiv1.LocalPolicyTargetReferenceWithSectionName{
+			LocalPolicyTargetReference: gwapiv1.LocalPolicyTargetReference{Group: gwapiv1.GroupName, Kind: kind, Name: name},
+		}
+		if sectionName != "" {
+			ref.SectionName = &sectionName
+		}
+		return ref
+	}
+	gatewayKinds := []schema.GroupKind{GatewayGroupKind}
+	listenerKinds := []schema.GroupKind{ListenerGroupKind}
+
+	policies := []Policy{
+		buildPolicy("gateway-policy", gatewayKinds, listenerKinds, targetRef("Gateway", "my-gateway", "")),
+		buildPolicy("listener-policy", gatewayKinds, listenerKinds, targetRef("Gateway", "my-gateway", "my-listener")),
+		buildPolicy("route-policy", gatewayKinds, listenerKinds, targetRef("HTTPRoute", "my-http-route", "")),
+		buildPolicy("route-rule-policy", []schema.GroupKind{GatewayGroupKind, HTTPRouteGroupKind}, listenerKinds, targetRef("HTTPRoute", "my-http-route", "rule-1")),
+		buildPolicy("any-section-policy", gatewayKinds, nil, targetRef("Gateway", "my-gateway", "my-listener")),
+		buildPolicy("unrestricted-policy", nil, nil, targetRef("HTTPRoute", "my-http-route", "")),
+		buildPolicy("missing-target-policy", gatewayKinds, listenerKinds, targetRef("HTTPRoute", "other-http-route", "")),
+	}
+
+	topology, err := NewGatewayAPITopology(
+		WithGatewayClasses(BuildGatewayClass()),
+		WithGateways(BuildGateway()),
+		WithHTTPRoutes(BuildHTTPRoute()),
+		ExpandGatewayListeners(),
+		ExpandHTTPRouteRules(),
+		WithGatewayAPITopologyPolicies(policies...),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	attachedPolicies := make(map[string][]string)
+	for _, targetable := range topology.Targetables().Items() {
+		for _, policy := range targetable.Policies() {
+			attachedPolicies[targetable.GetName()] = append(attachedPolicies[targetable.GetName()], policy.GetName())
+		}
+	}
+	expectedPolicies := map[string][]string{
+		"my-gateway":             {"gateway-policy"},
+		"my-gateway#my-listener": {"listener-policy", "any-section-policy"},
+		"my-http-route":          {"unrestricted-policy"},
+		"my-http-route#rule-1":   nil,
+		"my-gateway-class":       nil,
+	}
+	for name, expected := range expectedPolicies {
+		if !slices.Equal(attachedPolicies[name], expected) {
+			t.Errorf("expected policies attached to %s to be %v, got %v", name, expected, attachedPolicies[name])
+		}
+	}
+
+	refused := lo.Map(topology.RefusedPolicyAttachments(), func(refused RefusedPolicyAttachment, _ int) string {
+		return fmt.Sprintf("%s: %s", refused.Policy.GetName(), refused.Reason)
+	})
+	expectedRefused := []string{
+		"route-policy: TestPolicy cannot target kind HTTPRoute.gateway.networking.k8s.io",
+		"route-rule-policy: TestPolicy cannot target sections of kind HTTPRouteRule.gateway.networking.k8s.io",
+	}
+	if !slices.Equal(refused, expectedRefused) {
+		t.Errorf("expected refused policy attachments to be %v, got %v", expectedRefused, refused)
+	}
+
+	for _, policy := range topology.Policies().Items() {
+		targets := lo.Map(topology.Targetables().Children(policy), MapTargetableToLocatorFunc)
+		if lo.Contains([]string{"route-policy", "route-rule-policy"}, policy.GetName()) && len(targets) > 0 {
+			t.Errorf("expected no targets for refused policy %s, got %v", policy.GetName(), targets)
+		}
+	}
+}
+
+type allowedTargetsTestPolicy struct {
+	*TestPolicy
+
+	targetKinds  []schema.GroupKind
+	sectionKinds []schema.GroupKind
+}
+
+var _ PolicyWithAllowedTargets = &allowedTargetsTestPolicy{}
+
+func (p *allowedTargetsTestPolicy) AllowedTargetKinds() []schema.GroupKind {
+	return p.targetKinds
+}
+
+func (p *allowedTargetsTestPolicy) AllowedSectionKinds() []schema.GroupKind {
+	return p.sectionKinds
+}
+
+// TestGatewayAPITopologyWithRouteMatches tests for a topology of Gateway API resources where the matches of the
+// HTTPRouteRules and GRPCRouteRules are expanded into targetables that policies can target by section name.
+func TestGatewayAPITopologyWithRouteMatches(t *testing.T) {
+	httpRoute := BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+		r.Spec.Rules = []gwapiv1.HTTPRouteRule{
+			{
+				Matches: []gwapiv1.HTTPRouteMatch{
+					{Path: &gwapiv1.HTTPPathMatch{Value: ptr.To("/cars")}},
+					{Path: &gwapiv1.HTTPPathMatch{Value: ptr.To("/dolls")}, Method: ptr.To(gwapiv1.HTTPMethodPost)},
+				},
+				BackendRefs: []gwapiv1.HTTPBackendRef{BuildHTTPBackendRef()},
+			},
+			{
+				Name:        ptr.To(gwapiv1.SectionName("catch-all")),
+				BackendRefs: []gwapiv1.HTTPBackendRef{BuildHTTPBackendRef()},
+			},
+		}
+	})
+	grpcRoute := BuildGRPCRoute(func(r *gwapiv1.GRPCRoute) {
+		r.Spec.Rules[0].Name = ptr.To(gwapiv1.SectionName("toys"))
+		r.Spec.Rules[0].Matches = []gwapiv1.GRPCRouteMatch{
+			{Method: &gwapiv1.GRPCMethodMatch{Service: ptr.To("toystore.Toys"), Method: ptr.To("Get")}},
+		}
+	})
+	matchPolicy := buildPolicy(func(p *TestPolicy) {
+		p.Name = "match-policy"
+		p.Spec.TargetRef.Group = gwapiv1.GroupName
+		p.Spec.TargetRef.Kind = "HTTPRoute"
+		p.Spec.TargetRef.Name = "my-http-route"
+		p.Spec.TargetRef.SectionName = ptr.To(gwapiv1.SectionName("rule-1.match-2"))
+	})
+
+	topology, err := NewGatewayAPITopology(
+		WithHTTPRoutes(httpRoute),
+		WithGRPCRoutes(grpcRoute),
+		WithServices(BuildService()),
+		WithGatewayAPITopologyPolicies(matchPolicy),
+		ExpandHTTPRouteMatches(),
+		ExpandGRPCRouteMatches(),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expectedLinks := map[string][]string{
+		"my-http-route":                {"my-http-route#rule-1", "my-http-route#catch-all"},
+		"my-http-route#rule-1":         {"my-http-route#rule-1.match-1", "my-http-route#rule-1.match-2", "my-service"},
+		"my-http-route#catch-all":      {"my-service"},
+		"my-http-route#rule-1.match-1": {},
+		"my-http-route#rule-1.match-2": {},
+		"my-grpc-route":                {"my-grpc-route#toys"},
+		"my-grpc-route#toys":           {"my-grpc-route#toys.match-1", "my-service"},
+		"my-grpc-route#toys.match-1":   {},
+		"my-service":                   {},
+	}
+	links := make(map[string][]string)
+	for _, root := range topology.Targetables().Roots() {
+		linksFromTargetable(topology, root, links)
+	}
+	for from, tos := range links {
+		expectedTos := expectedLinks[from]
+		slices.Sort(expectedTos)
+		slices.Sort(tos)
+		if !slices.Equal(expectedTos, tos) {
+			t.Errorf("expected links from %s to be %v, got %v", from, expectedTos, tos)
+		}
+	}
+	if len(links) != len(expectedLinks) {
+		t.Errorf("expected %d targetables, got %d", len(expectedLinks), len(links))
+	}
+
+	match, found := lo.Find(topology.Targetables().Items(), func(t Targetable) bool {
+		return t.GetLocator() == "httproute.gateway.networking.k8s.io:my-namespace/my-http-route#rule-1.match-2"
+	})
+	if !found {
+		t.Fatalf("expected match rule-1.match-2 of my-http-route in the topology")
+	}
+	if policies := match.Policies(); len(policies) != 1 || policies[0].GetLocator() != matchPolicy.GetLocator() {
+		t.Errorf("expected policy %s attached to match rule-1.match-2 of my-http-route, got %v", matchPolicy.GetLocator(), policies)
+	}
+	if m := match.(*HTTPRouteMatch); m.Method == nil || *m.Method != gwapiv1.HTTPMethodPost {
+		t.Errorf("expected match rule-1.match-2 of my-http-route to be the second match of the rule, got %v", m.HTTPRouteMatch)
+	}
+}
+
 // TestRouteRuleNameHandling tests that route rules use the experimental Name field when present,
 // and fall back to auto-generated names when absent.
 func TestRouteRuleNameHandling(t *testing.T) {
@@ -506,3 +2287,107 @@ func TestRouteRuleNameHandling(t *testing.T) {
 		}
 	})
 }
+
+const (
+	benchmarkGateways   = 100
+	benchmarkHTTPRoutes = 10000
+)
+
+// buildBenchmarkGatewayAPIResources builds a large set of Gateways, HTTPRoutes and Services, where each HTTPRoute
+// attaches to one Gateway and routes to its own Service
+func buildBenchmarkGatewayAPIResources() ([]*Gateway, []*Listener, []*HTTPRoute, []*Service) {
+	gateways := lo.Times(benchmarkGateways, func(i int) *Gateway {
+		return &Gateway{Gateway: BuildGateway(func(g *gwapiv1.Gateway) {
+			g.Name = fmt.Sprintf("gateway-%d", i)
+		})}
+	})
+	listeners := lo.FlatMap(gateways, ListenersFromGatewayFunc)
+	httpRoutes := lo.Times(benchmarkHTTPRoutes, func(i int) *HTTPRoute {
+		return &HTTPRoute{HTTPRoute: BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Name = fmt.Sprintf("http-route-%d", i)
+			r.Spec.ParentRefs[0].Name = gwapiv1.ObjectName(fmt.Sprintf("gateway-%d", i%benchmarkGateways))
+			r.Spec.Rules[0].BackendRefs[0].Name = gwapiv1.ObjectName(fmt.Sprintf("service-%d", i))
+		})}
+	})
+	services := lo.Times(benchmarkHTTPRoutes, func(i int) *Service {
+		return &Service{Service: BuildService(func(s *core.Service) {
+			s.Name = fmt.Sprintf("service-%d", i)
+		})}
+	})
+	return gateways, listeners, httpRoutes, services
+}
+
+func benchmarkLinkFunc[T Object](b *testing.B, newLinkFunc func() LinkFunc, children []T) {
+	b.ReportAllocs()
+	for b.Loop() {
+		link := newLinkFunc()
+		for _, child := range children {
+			link.Func(child)
+		}
+	}
+}
+
+func BenchmarkLinkGatewayToHTTPRouteFunc(b *testing.B) {
+	gateways, _, httpRoutes, _ := buildBenchmarkGatewayAPIResources()
+	benchmarkLinkFunc(b, func() LinkFunc { return LinkGatewayToHTTPRouteFunc(gateways) }, httpRoutes)
+}
+
+func BenchmarkLinkListenerToHTTPRouteFunc(b *testing.B) {
+	gateways, listeners, httpRoutes, _ := buildBenchmarkGatewayAPIResources()
+	benchmarkLinkFunc(b, func() LinkFunc { return LinkListenerToHTTPRouteFunc(gateways, listeners) }, httpRoutes)
+}
+
+func BenchmarkLinkHTTPRouteToServiceFunc(b *testing.B) {
+	_, _, httpRoutes, services := buildBenchmarkGatewayAPIResources()
+	benchmarkLinkFunc(b, func() LinkFunc { return LinkHTTPRouteToServiceFunc(httpRoutes, false) }, services)
+}
+
+func BenchmarkLinkHTTPRouteRuleToServicePortFunc(b *testing.B) {
+	_, _, httpRoutes, services := buildBenchmarkGatewayAPIResources()
+	httpRoutes = lo.Map(httpRoutes, func(httpRoute *HTTPRoute, _ int) *HTTPRoute {
+		httpRoute.Spec.Rules[0].BackendRefs[0].Port = ptr.To(gwapiv1.PortNumber(80))
+		return httpRoute
+	})
+	httpRouteRules := lo.FlatMap(httpRoutes, HTTPRouteRulesFromHTTPRouteFunc)
+	servicePorts := lo.FlatMap(services, ServicePortsFromServiceFunc)
+	benchmarkLinkFunc(b, func() LinkFunc { return LinkHTTPRouteRuleToServicePortFunc(httpRouteRules) }, servicePorts)
+}
+
+// TestGatewayAPITopologyParallelism tests that topologies built concurrently are identical to the ones built serially.
+func TestGatewayAPITopologyParallelism(t *testing.T) {
+	resources := BuildComplexGatewayAPITopology()
+	newTopology := func(parallelism int) *Topology {
+		topology, err := NewGatewayAPITopology(
+			WithGatewayClasses(resources.GatewayClasses...),
+			WithGateways(resources.Gateways...),
+			WithHTTPRoutes(resources.HTTPRoutes...),
+			WithGRPCRoutes(resources.GRPCRoutes...),
+			WithTCPRoutes(resources.TCPRoutes...),
+			WithTLSRoutes(resources.TLSRoutes...),
+			WithUDPRoutes(resources.UDPRoutes...),
+			WithServices(resources.Services...),
+			WithGatewayAPITopologyPolicies(buildPolicy()),
+			ExpandGatewayListeners(),
+			ExpandHTTPRouteRules(),
+			ExpandGRPCRouteRules(),
+			ExpandTCPRouteRules(),
+			ExpandTLSRouteRules(),
+			ExpandUDPRouteRules(),
+			ExpandServicePorts(),
+			WithGatewayAPITopologyParallelism(parallelism),
+		)
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		return topology
+	}
+
+	expected := newTopology(1).ToDot()
+	for _, parallelism := range []int{2, 4, 16} {
+		for range 10 {
+			if actual := newTopology(parallelism).ToDot(); actual != expected {
+				t.Fatalf("expected topology built with parallelism %d to be identical to the serial one, got:\n%s\nexpected:\n%s", parallelism, actual, expected)
+			}
+		}
+	}
+}
@@ -2,6 +2,7 @@ package machinery
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/samber/lo"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -15,10 +16,14 @@ var (
 	GatewayClassGroupKind     = gwapiv1.SchemeGroupVersion.WithKind("GatewayClass").GroupKind()
 	GatewayGroupKind          = gwapiv1.SchemeGroupVersion.WithKind("Gateway").GroupKind()
 	ListenerGroupKind         = gwapiv1.SchemeGroupVersion.WithKind("Listener").GroupKind()
+	ListenerSetGroupKind      = gwapiv1.SchemeGroupVersion.WithKind("ListenerSet").GroupKind()
+	HostnameGroupKind         = gwapiv1.SchemeGroupVersion.WithKind("Hostname").GroupKind()
 	HTTPRouteGroupKind        = gwapiv1.SchemeGroupVersion.WithKind("HTTPRoute").GroupKind()
 	HTTPRouteRuleGroupKind    = gwapiv1.SchemeGroupVersion.WithKind("HTTPRouteRule").GroupKind()
 	GRPCRouteGroupKind        = gwapiv1.SchemeGroupVersion.WithKind("GRPCRoute").GroupKind()
 	GRPCRouteRuleGroupKind    = gwapiv1.SchemeGroupVersion.WithKind("GRPCRouteRule").GroupKind()
+	HTTPRouteMatchGroupKind   = gwapiv1.SchemeGroupVersion.WithKind("HTTPRouteMatch").GroupKind()
+	GRPCRouteMatchGroupKind   = gwapiv1.SchemeGroupVersion.WithKind("GRPCRouteMatch").GroupKind()
 	ReferenceGrantGroupKind   = gwapiv1beta1.SchemeGroupVersion.WithKind("ReferenceGrant").GroupKind()
 	BackendTLSPolicyGroupKind = gwapiv1alpha3.SchemeGroupVersion.WithKind("BackendTLSPolicy").GroupKind()
 	TCPRouteGroupKind         = gwapiv1.SchemeGroupVersion.WithKind("TCPRoute").GroupKind()
@@ -76,10 +81,34 @@ func (g *Gateway) Policies() []Policy {
 	return g.attachedPolicies
 }
 
+type ListenerSet struct {
+	*gwapiv1.ListenerSet
+
+	// Gateway is the parent Gateway of the ListenerSet, when known.
+	Gateway          *Gateway
+	attachedPolicies []Policy
+}
+
+var _ Targetable = &ListenerSet{}
+
+func (l *ListenerSet) GetLocator() string {
+	return LocatorFromObject(l)
+}
+
+func (l *ListenerSet) SetPolicies(policies []Policy) {
+	l.attachedPolicies = policies
+}
+
+func (l *ListenerSet) Policies() []Policy {
+	return l.attachedPolicies
+}
+
 type Listener struct {
 	*gwapiv1.Listener
 
-	Gateway          *Gateway
+	Gateway *Gateway
+	// ListenerSet is the ListenerSet that defines the Listener, for Listeners that are not defined in the Gateway.
+	ListenerSet      *ListenerSet
 	attachedPolicies []Policy
 }
 
@@ -96,15 +125,15 @@ func (l *Listener) GroupVersionKind() schema.GroupVersionKind {
 func (l *Listener) SetGroupVersionKind(schema.GroupVersionKind) {}
 
 func (l *Listener) GetLocator() string {
-	return namespacedSectionName(LocatorFromObject(l.Gateway), l.Name)
+	return namespacedSectionName(LocatorFromObject(l.parent()), l.Name)
 }
 
 func (l *Listener) GetNamespace() string {
-	return l.Gateway.GetNamespace()
+	return l.parent().GetNamespace()
 }
 
 func (l *Listener) GetName() string {
-	return namespacedSectionName(l.Gateway.GetName(), l.Name)
+	return namespacedSectionName(l.parent().GetName(), l.Name)
 }
 
 func (l *Listener) SetPolicies(policies []Policy) {
@@ -115,9 +144,79 @@ func (l *Listener) Policies() []Policy {
 	return l.attachedPolicies
 }
 
+// parent returns the object that defines the Listener, i.e. the ListenerSet if any, otherwise the Gateway
+func (l *Listener) parent() Object {
+	if l.ListenerSet != nil {
+		return l.ListenerSet
+	}
+	return l.Gateway
+}
+
+// Hostname is a virtual targetable that represents a hostname served by a gateway Listener, i.e. the hostname of the
+// Listener or the hostname of a route attached to the Listener, narrowed down to the hostname of the Listener.
+// Hostnames are sections of the Gateway or ListenerSet that defines the Listener that serves them, so the same hostname
+// served by different Listeners is represented by different targetables, e.g. `my-gateway#my-listener.api.example.com`.
+type Hostname struct {
+	Hostname gwapiv1.Hostname
+
+	Listener         *Listener
+	attachedPolicies []Policy
+}
+
+var _ Targetable = &Hostname{}
+
+func (h *Hostname) GroupVersionKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{
+		Group:   gwapiv1.GroupName,
+		Version: gwapiv1.GroupVersion.Version,
+		Kind:    "Hostname",
+	}
+}
+
+func (h *Hostname) SetGroupVersionKind(schema.GroupVersionKind) {}
+
+func (h *Hostname) GetLocator() string {
+	return namespacedSectionName(LocatorFromObject(h.Listener.parent()), h.SectionName())
+}
+
+func (h *Hostname) GetNamespace() string {
+	return h.Listener.GetNamespace()
+}
+
+func (h *Hostname) GetName() string {
+	return namespacedSectionName(h.Listener.parent().GetName(), h.SectionName())
+}
+
+// SectionName returns the name of the section of the Gateway or ListenerSet that stands for the hostname, i.e. the
+// name of the Listener and the hostname joined by a dot, with the `*` label of a wildcard hostname spelled out as
+// `wildcard`, e.g. `my-listener.api.example.com` or `my-listener.wildcard.example.com`.
+// The section name is valid as the `sectionName` of a policy target reference, as long as it does not exceed the
+// maximum length of 253 characters.
+func (h *Hostname) SectionName() gwapiv1.SectionName {
+	return gwapiv1.SectionName(fmt.Sprintf("%s.%s", h.Listener.Name, strings.Replace(string(h.Hostname), "*", "wildcard", 1)))
+}
+
+func (h *Hostname) SetPolicies(policies []Policy) {
+	h.attachedPolicies = policies
+}
+
+func (h *Hostname) Policies() []Policy {
+	return h.attachedPolicies
+}
+
+// RouteReferenceGrants is the outcome of checking the backendRefs of a route against the ReferenceGrants, when the
+// topology requires them.
+type RouteReferenceGrants struct {
+	// RefusedBackendRefs are the backendRefs of the route to objects in other namespaces that are not permitted by any
+	// ReferenceGrant. Links to the referents of these backendRefs are not established.
+	RefusedBackendRefs []gwapiv1.BackendRef
+}
+
 type HTTPRoute struct {
 	*gwapiv1.HTTPRoute
 
+	RouteReferenceGrants
+
 	attachedPolicies []Policy
 }
 
@@ -175,9 +274,51 @@ func (r *HTTPRouteRule) Policies() []Policy {
 	return r.attachedPolicies
 }
 
+type HTTPRouteMatch struct {
+	*gwapiv1.HTTPRouteMatch
+
+	HTTPRouteRule    *HTTPRouteRule
+	Name             gwapiv1.SectionName
+	attachedPolicies []Policy
+}
+
+var _ Targetable = &HTTPRouteMatch{}
+
+func (m *HTTPRouteMatch) GroupVersionKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{
+		Group:   gwapiv1.GroupName,
+		Version: gwapiv1.GroupVersion.Version,
+		Kind:    "HTTPRouteMatch",
+	}
+}
+
+func (m *HTTPRouteMatch) SetGroupVersionKind(schema.GroupVersionKind) {}
+
+func (m *HTTPRouteMatch) GetLocator() string {
+	return namespacedSectionName(LocatorFromObject(m.HTTPRouteRule.HTTPRoute), m.Name)
+}
+
+func (m *HTTPRouteMatch) GetNamespace() string {
+	return m.HTTPRouteRule.GetNamespace()
+}
+
+func (m *HTTPRouteMatch) GetName() string {
+	return namespacedSectionName(m.HTTPRouteRule.HTTPRoute.Name, m.Name)
+}
+
+func (m *HTTPRouteMatch) SetPolicies(policies []Policy) {
+	m.attachedPolicies = policies
+}
+
+func (m *HTTPRouteMatch) Policies() []Policy {
+	return m.attachedPolicies
+}
+
 type GRPCRoute struct {
 	*gwapiv1.GRPCRoute
 
+	RouteReferenceGrants
+
 	attachedPolicies []Policy
 }
 
@@ -234,9 +375,51 @@ func (r *GRPCRouteRule) Policies() []Policy {
 	return r.attachedPolicies
 }
 
+type GRPCRouteMatch struct {
+	*gwapiv1.GRPCRouteMatch
+
+	GRPCRouteRule    *GRPCRouteRule
+	Name             gwapiv1.SectionName
+	attachedPolicies []Policy
+}
+
+var _ Targetable = &GRPCRouteMatch{}
+
+func (m *GRPCRouteMatch) GroupVersionKind() schema.GroupVersionKind {
+	return schema.GroupVersionKind{
+		Group:   gwapiv1.GroupName,
+		Version: gwapiv1.GroupVersion.Version,
+		Kind:    "GRPCRouteMatch",
+	}
+}
+
+func (m *GRPCRouteMatch) SetGroupVersionKind(schema.GroupVersionKind) {}
+
+func (m *GRPCRouteMatch) GetLocator() string {
+	return namespacedSectionName(LocatorFromObject(m.GRPCRouteRule.GRPCRoute), m.Name)
+}
+
+func (m *GRPCRouteMatch) GetNamespace() string {
+	return m.GRPCRouteRule.GetNamespace()
+}
+
+func (m *GRPCRouteMatch) GetName() string {
+	return namespacedSectionName(m.GRPCRouteRule.GRPCRoute.Name, m.Name)
+}
+
+func (m *GRPCRouteMatch) SetPolicies(policies []Policy) {
+	m.attachedPolicies = policies
+}
+
+func (m *GRPCRouteMatch) Policies() []Policy {
+	return m.attachedPolicies
+}
+
 type TCPRoute struct {
 	*gwapiv1.TCPRoute
 
+	RouteReferenceGrants
+
 	attachedPolicies []Policy
 }
 
@@ -297,6 +480,8 @@ func (r *TCPRouteRule) Policies() []Policy {
 type TLSRoute struct {
 	*gwapiv1.TLSRoute
 
+	RouteReferenceGrants
+
 	attachedPolicies []Policy
 }
 
@@ -357,6 +542,8 @@ func (r *TLSRouteRule) Policies() []Policy {
 type UDPRoute struct {
 	*gwapiv1.UDPRoute
 
+	RouteReferenceGrants
+
 	attachedPolicies []Policy
 }
 
@@ -444,6 +631,9 @@ func (t NamespacedPolicyTargetReference) GetLocator() string {
 }
 
 func (t NamespacedPolicyTargetReference) GetNamespace() string {
+	if isClusterScoped(t.GroupVersionKind().GroupKind()) {
+		return ""
+	}
 	return string(ptr.Deref(t.Namespace, gwapiv1.Namespace(t.PolicyNamespace)))
 }
 
@@ -475,6 +665,9 @@ func (t LocalPolicyTargetReference) GetLocator() string {
 }
 
 func (t LocalPolicyTargetReference) GetNamespace() string {
+	if isClusterScoped(t.GroupVersionKind().GroupKind()) {
+		return ""
+	}
 	return t.PolicyNamespace
 }
 
@@ -482,6 +675,12 @@ func (t LocalPolicyTargetReference) GetName() string {
 	return string(t.LocalPolicyTargetReference.Name)
 }
 
+// isClusterScoped tells whether the targetables of a kind are cluster-scoped, in which case local target references
+// to them point to objects outside the namespace of the policy
+func isClusterScoped(gk schema.GroupKind) bool {
+	return gk == NamespaceGroupKind || gk == GatewayClassGroupKind
+}
+
 type LocalPolicyTargetReferenceWithSectionName struct {
 	gwapiv1.LocalPolicyTargetReferenceWithSectionName
 	PolicyNamespace string
@@ -506,6 +705,9 @@ func (t LocalPolicyTargetReferenceWithSectionName) GetLocator() string {
 }
 
 func (t LocalPolicyTargetReferenceWithSectionName) GetNamespace() string {
+	if isClusterScoped(t.GroupVersionKind().GroupKind()) {
+		return ""
+	}
 	return t.PolicyNamespace
 }
 
@@ -528,6 +730,16 @@ func (o *ReferenceGrant) GetLocator() string {
 	return LocatorFromObject(o)
 }
 
+// Permits tells whether the ReferenceGrant allows objects of the `from` kind in the `fromNamespace` namespace to refer
+// to the object of the `to` kind and `toName` name in the namespace of the ReferenceGrant.
+func (o *ReferenceGrant) Permits(from schema.GroupKind, fromNamespace string, to schema.GroupKind, toName string) bool {
+	return lo.ContainsBy(o.Spec.From, func(f gwapiv1beta1.ReferenceGrantFrom) bool {
+		return string(f.Group) == from.Group && string(f.Kind) == from.Kind && string(f.Namespace) == fromNamespace
+	}) && lo.ContainsBy(o.Spec.To, func(t gwapiv1beta1.ReferenceGrantTo) bool {
+		return string(t.Group) == to.Group && string(t.Kind) == to.Kind && (t.Name == nil || string(*t.Name) == toName)
+	})
+}
+
 // These are wrappers for Gateway API types so instances can be used as policies in the topology.
 
 type BackendTLSPolicy struct {
@@ -0,0 +1,77 @@
+//go:build unit || integration
+
+package machinery
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func BuildIngressClass(f ...func(*networkingv1.IngressClass)) *networkingv1.IngressClass {
+	ic := &networkingv1.IngressClass{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: networkingv1.SchemeGroupVersion.String(),
+			Kind:       "IngressClass",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "my-ingress-class",
+		},
+		Spec: networkingv1.IngressClassSpec{
+			Controller: "example.com/my-ingress-controller",
+		},
+	}
+	for _, fn := range f {
+		fn(ic)
+	}
+	return ic
+}
+
+func BuildIngress(f ...func(*networkingv1.Ingress)) *networkingv1.Ingress {
+	i := &networkingv1.Ingress{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: networkingv1.SchemeGroupVersion.String(),
+			Kind:       "Ingress",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-ingress",
+			Namespace: "my-namespace",
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: ptr.To("my-ingress-class"),
+			Rules: []networkingv1.IngressRule{
+				{
+					Host: "example.com",
+					IngressRuleValue: networkingv1.IngressRuleValue{
+						HTTP: &networkingv1.HTTPIngressRuleValue{
+							Paths: []networkingv1.HTTPIngressPath{
+								BuildIngressPath(),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for _, fn := range f {
+		fn(i)
+	}
+	return i
+}
+
+func BuildIngressPath(f ...func(*networkingv1.HTTPIngressPath)) networkingv1.HTTPIngressPath {
+	p := networkingv1.HTTPIngressPath{
+		Path:     "/",
+		PathType: ptr.To(networkingv1.PathTypePrefix),
+		Backend: networkingv1.IngressBackend{
+			Service: &networkingv1.IngressServiceBackend{
+				Name: "my-service",
+				Port: networkingv1.ServiceBackendPort{Number: 80},
+			},
+		},
+	}
+	for _, fn := range f {
+		fn(&p)
+	}
+	return p
+}
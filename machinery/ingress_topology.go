@@ -0,0 +1,399 @@
+package machinery
+
+import (
+	"fmt"
+
+	"github.com/samber/lo"
+	core "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+type IngressTopologyOptions struct {
+	Namespaces     []*Namespace
+	IngressClasses []*IngressClass
+	Ingresses      []*Ingress
+	Services       []*Service
+	Policies       []Policy
+	Objects        []Object
+	Links          []LinkFunc
+
+	ExpandIngressRules bool
+	ExpandIngressPaths bool
+	ExpandServicePorts bool
+
+	allowTopologyLoops bool
+}
+
+type IngressTopologyOptionsFunc func(*IngressTopologyOptions)
+
+// WithIngressTopologyNamespaces adds namespaces to the options to initialize a new Ingress topology.
+func WithIngressTopologyNamespaces(namespaces ...*core.Namespace) IngressTopologyOptionsFunc {
+	return func(o *IngressTopologyOptions) {
+		o.Namespaces = append(o.Namespaces, lo.Map(namespaces, func(namespace *core.Namespace, _ int) *Namespace {
+			return &Namespace{Namespace: namespace}
+		})...)
+	}
+}
+
+// WithIngressClasses adds ingress classes to the options to initialize a new Ingress topology.
+func WithIngressClasses(ingressClasses ...*networkingv1.IngressClass) IngressTopologyOptionsFunc {
+	return func(o *IngressTopologyOptions) {
+		o.IngressClasses = append(o.IngressClasses, lo.Map(ingressClasses, func(ingressClass *networkingv1.IngressClass, _ int) *IngressClass {
+			return &IngressClass{IngressClass: ingressClass}
+		})...)
+	}
+}
+
+// WithIngresses adds ingresses to the options to initialize a new Ingress topology.
+func WithIngresses(ingresses ...*networkingv1.Ingress) IngressTopologyOptionsFunc {
+	return func(o *IngressTopologyOptions) {
+		o.Ingresses = append(o.Ingresses, lo.Map(ingresses, func(ingress *networkingv1.Ingress, _ int) *Ingress {
+			return &Ingress{Ingress: ingress}
+		})...)
+	}
+}
+
+// WithIngressTopologyServices adds services to the options to initialize a new Ingress topology.
+func WithIngressTopologyServices(services ...*core.Service) IngressTopologyOptionsFunc {
+	return func(o *IngressTopologyOptions) {
+		o.Services = append(o.Services, lo.Map(services, func(service *core.Service, _ int) *Service {
+			return &Service{Service: service}
+		})...)
+	}
+}
+
+// WithIngressTopologyPolicies adds policies to the options to initialize a new Ingress topology.
+func WithIngressTopologyPolicies(policies ...Policy) IngressTopologyOptionsFunc {
+	return func(o *IngressTopologyOptions) {
+		o.Policies = append(o.Policies, policies...)
+	}
+}
+
+// WithIngressTopologyObjects adds objects to the options to initialize a new Ingress topology.
+func WithIngressTopologyObjects(objects ...Object) IngressTopologyOptionsFunc {
+	return func(o *IngressTopologyOptions) {
+		o.Objects = append(o.Objects, objects...)
+	}
+}
+
+// WithIngressTopologyLinks adds link functions to the options to initialize a new Ingress topology.
+func WithIngressTopologyLinks(links ...LinkFunc) IngressTopologyOptionsFunc {
+	return func(o *IngressTopologyOptions) {
+		o.Links = append(o.Links, links...)
+	}
+}
+
+// ExpandIngressRules adds targetable ingress rules to the options to initialize a new Ingress topology.
+func ExpandIngressRules() IngressTopologyOptionsFunc {
+	return func(o *IngressTopologyOptions) {
+		o.ExpandIngressRules = true
+	}
+}
+
+// ExpandIngressPaths adds targetable ingress paths to the options to initialize a new Ingress topology.
+// The ingress rules are expanded as well. The section name of each path is the name of the rule followed by the
+// position of the path in the rule, e.g. `rule-1.path-2`, so policies can target a single path of a rule.
+func ExpandIngressPaths() IngressTopologyOptionsFunc {
+	return func(o *IngressTopologyOptions) {
+		o.ExpandIngressRules = true
+		o.ExpandIngressPaths = true
+	}
+}
+
+// ExpandIngressServicePorts adds targetable service ports to the options to initialize a new Ingress topology.
+func ExpandIngressServicePorts() IngressTopologyOptionsFunc {
+	return func(o *IngressTopologyOptions) {
+		o.ExpandServicePorts = true
+	}
+}
+
+// AllowIngressTopologyLoops allows the Ingress topology to contain loops.
+func AllowIngressTopologyLoops() IngressTopologyOptionsFunc {
+	return func(o *IngressTopologyOptions) {
+		o.allowTopologyLoops = true
+	}
+}
+
+// NewIngressTopology returns a topology of Ingress resources (networking.k8s.io/v1), so the same policies and merge
+// strategies used with Gateway API topologies can be applied to the traffic managed with Ingresses.
+//
+// The links in the topology are:
+//
+//	IngressClass -> Ingress -> IngressRule -> IngressPath -> Service -> ServicePort
+//	Namespace -> Ingress
+//
+// The Services are linked from the lowest level of the Ingresses expanded, i.e. from the Ingresses when neither the
+// rules nor the paths are expanded, from the rules when only the rules are expanded, or from the paths otherwise. The
+// Services of the default backends are always linked from the Ingresses. Since the backends of Ingresses always specify
+// a port, when the service ports are expanded the backends are linked to the service ports instead, matched by port
+// number or name.
+func NewIngressTopology(options ...IngressTopologyOptionsFunc) (*Topology, error) {
+	o := &IngressTopologyOptions{}
+	for _, f := range options {
+		f(o)
+	}
+
+	opts := []TopologyOptionsFunc{
+		WithObjects(o.Objects...),
+		WithPolicies(o.Policies...),
+		WithTargetables(o.Namespaces...),
+		WithTargetables(o.IngressClasses...),
+		WithTargetables(o.Ingresses...),
+		WithTargetables(o.Services...),
+		WithLinks(o.Links...),
+		WithLinks(
+			LinkIngressClassToIngressFunc(o.IngressClasses),           // IngressClass -> Ingress
+			linkNamespaceToObjectFunc(o.Namespaces, IngressGroupKind), // Namespace -> Ingress
+		),
+	}
+
+	var (
+		ingressRules []*IngressRule
+		ingressPaths []*IngressPath
+	)
+	if o.ExpandIngressRules {
+		ingressRules = lo.FlatMap(o.Ingresses, IngressRulesFromIngressFunc)
+		opts = append(opts, WithTargetables(ingressRules...))
+		opts = append(opts, WithLinks(LinkIngressToIngressRuleFunc())) // Ingress -> IngressRule
+	}
+	if o.ExpandIngressPaths {
+		ingressPaths = lo.FlatMap(ingressRules, IngressPathsFromIngressRuleFunc)
+		opts = append(opts, WithTargetables(ingressPaths...))
+		opts = append(opts, WithLinks(LinkIngressRuleToIngressPathFunc())) // IngressRule -> IngressPath
+	}
+
+	// link the backends from the lowest level of the Ingresses expanded
+	if o.ExpandServicePorts {
+		opts = append(opts, WithTargetables(lo.FlatMap(o.Services, ServicePortsFromServiceFunc)...))
+		opts = append(opts, WithLinks(
+			LinkServiceToServicePortFunc(),                                   // Service -> ServicePort
+			LinkIngressToServicePortFunc(o.Ingresses, !o.ExpandIngressRules), // Ingress -> ServicePort
+		))
+		switch {
+		case o.ExpandIngressPaths:
+			opts = append(opts, WithLinks(LinkIngressPathToServicePortFunc(ingressPaths))) // IngressPath -> ServicePort
+		case o.ExpandIngressRules:
+			opts = append(opts, WithLinks(LinkIngressRuleToServicePortFunc(ingressRules))) // IngressRule -> ServicePort
+		}
+	} else {
+		opts = append(opts, WithLinks(LinkIngressToServiceFunc(o.Ingresses, !o.ExpandIngressRules))) // Ingress -> Service
+		switch {
+		case o.ExpandIngressPaths:
+			opts = append(opts, WithLinks(LinkIngressPathToServiceFunc(ingressPaths))) // IngressPath -> Service
+		case o.ExpandIngressRules:
+			opts = append(opts, WithLinks(LinkIngressRuleToServiceFunc(ingressRules))) // IngressRule -> Service
+		}
+	}
+
+	if o.allowTopologyLoops {
+		opts = append(opts, AllowLoops())
+	}
+
+	return NewTopology(opts...)
+}
+
+// IngressRulesFromIngressFunc returns a list of targetable IngressRules from a targetable Ingress.
+func IngressRulesFromIngressFunc(ingress *Ingress, _ int) []*IngressRule {
+	return lo.Map(ingress.Spec.Rules, func(rule networkingv1.IngressRule, i int) *IngressRule {
+		return &IngressRule{
+			IngressRule: &rule,
+			Ingress:     ingress,
+			Name:        gwapiv1.SectionName(fmt.Sprintf("rule-%d", i+1)),
+		}
+	})
+}
+
+// IngressPathsFromIngressRuleFunc returns a list of targetable IngressPaths from a targetable IngressRule.
+func IngressPathsFromIngressRuleFunc(ingressRule *IngressRule, _ int) []*IngressPath {
+	if ingressRule.HTTP == nil {
+		return nil
+	}
+	return lo.Map(ingressRule.HTTP.Paths, func(path networkingv1.HTTPIngressPath, i int) *IngressPath {
+		return &IngressPath{
+			HTTPIngressPath: &path,
+			IngressRule:     ingressRule,
+			Name:            gwapiv1.SectionName(fmt.Sprintf("%s.path-%d", ingressRule.Name, i+1)),
+		}
+	})
+}
+
+// LinkIngressClassToIngressFunc returns a link function that teaches a topology how to link Ingresses from known
+// IngressClasses, based on the Ingress's `ingressClassName` field or, if not set, on the legacy `kubernetes.io/ingress.class`
+// annotation. Ingresses that specify no class are linked from the IngressClasses annotated as default.
+func LinkIngressClassToIngressFunc(ingressClasses []*IngressClass) LinkFunc {
+	return IndexedLinkFunc(IngressClassGroupKind, IngressGroupKind, ingressClasses,
+		func(ingressClass *IngressClass) []string {
+			keys := []string{ingressClass.Name}
+			if ingressClass.Annotations[networkingv1.AnnotationIsDefaultIngressClass] == "true" {
+				keys = append(keys, "")
+			}
+			return keys
+		},
+		func(child Object) []string {
+			ingress := child.(*Ingress)
+			if ingress.Spec.IngressClassName != nil {
+				return []string{*ingress.Spec.IngressClassName}
+			}
+			return []string{ingress.Annotations[networkingv1beta1.AnnotationIngressClass]}
+		},
+	)
+}
+
+// LinkIngressToIngressRuleFunc returns a link function that teaches a topology how to link IngressRules from the
+// Ingress they are strongly related to.
+func LinkIngressToIngressRuleFunc() LinkFunc {
+	return LinkFunc{
+		From: IngressGroupKind,
+		To:   IngressRuleGroupKind,
+		Func: func(child Object) []Object {
+			ingressRule := child.(*IngressRule)
+			return []Object{ingressRule.Ingress}
+		},
+	}
+}
+
+// LinkIngressRuleToIngressPathFunc returns a link function that teaches a topology how to link IngressPaths from the
+// IngressRule they are strongly related to.
+func LinkIngressRuleToIngressPathFunc() LinkFunc {
+	return LinkFunc{
+		From: IngressRuleGroupKind,
+		To:   IngressPathGroupKind,
+		Func: func(child Object) []Object {
+			ingressPath := child.(*IngressPath)
+			return []Object{ingressPath.IngressRule}
+		},
+	}
+}
+
+// LinkIngressToServiceFunc returns a link function that teaches a topology how to link Services from known Ingresses,
+// based on the Ingress's `defaultBackend` field and, if `withRules` is set, on the backends of the paths of all the
+// rules of the Ingress.
+func LinkIngressToServiceFunc(ingresses []*Ingress, withRules bool) LinkFunc {
+	return IndexedLinkFunc(IngressGroupKind, ServiceGroupKind, ingresses,
+		func(ingress *Ingress) []string {
+			return lo.FilterMap(ingressBackends(ingress, withRules), ingressServiceKeyFunc(ingress.Namespace))
+		},
+		serviceKeys,
+	)
+}
+
+// LinkIngressRuleToServiceFunc returns a link function that teaches a topology how to link Services from known
+// IngressRules, based on the backends of the paths of the rule.
+func LinkIngressRuleToServiceFunc(ingressRules []*IngressRule) LinkFunc {
+	return IndexedLinkFunc(IngressRuleGroupKind, ServiceGroupKind, ingressRules,
+		func(ingressRule *IngressRule) []string {
+			return lo.FilterMap(ingressRuleBackends(ingressRule.IngressRule), ingressServiceKeyFunc(ingressRule.Ingress.Namespace))
+		},
+		serviceKeys,
+	)
+}
+
+// LinkIngressPathToServiceFunc returns a link function that teaches a topology how to link Services from known
+// IngressPaths, based on the backend of the path.
+func LinkIngressPathToServiceFunc(ingressPaths []*IngressPath) LinkFunc {
+	return IndexedLinkFunc(IngressPathGroupKind, ServiceGroupKind, ingressPaths,
+		func(ingressPath *IngressPath) []string {
+			return lo.FilterMap([]networkingv1.IngressBackend{ingressPath.Backend}, ingressServiceKeyFunc(ingressPath.GetNamespace()))
+		},
+		serviceKeys,
+	)
+}
+
+// LinkIngressToServicePortFunc returns a link function that teaches a topology how to link service ports from known
+// Ingresses, based on the port number or name of the Ingress's `defaultBackend` field and, if `withRules` is set, of
+// the backends of the paths of all the rules of the Ingress.
+func LinkIngressToServicePortFunc(ingresses []*Ingress, withRules bool) LinkFunc {
+	return IndexedLinkFunc(IngressGroupKind, ServicePortGroupKind, ingresses,
+		func(ingress *Ingress) []string {
+			return lo.FilterMap(ingressBackends(ingress, withRules), ingressServicePortKeyFunc(ingress.Namespace))
+		},
+		ingressServicePortKeys,
+	)
+}
+
+// LinkIngressRuleToServicePortFunc returns a link function that teaches a topology how to link service ports from known
+// IngressRules, based on the port number or name of the backends of the paths of the rule.
+func LinkIngressRuleToServicePortFunc(ingressRules []*IngressRule) LinkFunc {
+	return IndexedLinkFunc(IngressRuleGroupKind, ServicePortGroupKind, ingressRules,
+		func(ingressRule *IngressRule) []string {
+			return lo.FilterMap(ingressRuleBackends(ingressRule.IngressRule), ingressServicePortKeyFunc(ingressRule.Ingress.Namespace))
+		},
+		ingressServicePortKeys,
+	)
+}
+
+// LinkIngressPathToServicePortFunc returns a link function that teaches a topology how to link service ports from known
+// IngressPaths, based on the port number or name of the backend of the path.
+func LinkIngressPathToServicePortFunc(ingressPaths []*IngressPath) LinkFunc {
+	return IndexedLinkFunc(IngressPathGroupKind, ServicePortGroupKind, ingressPaths,
+		func(ingressPath *IngressPath) []string {
+			return lo.FilterMap([]networkingv1.IngressBackend{ingressPath.Backend}, ingressServicePortKeyFunc(ingressPath.GetNamespace()))
+		},
+		ingressServicePortKeys,
+	)
+}
+
+// ingressBackends returns the default backend of an Ingress and, if `withRules` is set, the backends of the paths of
+// all the rules of the Ingress
+func ingressBackends(ingress *Ingress, withRules bool) []networkingv1.IngressBackend {
+	var backends []networkingv1.IngressBackend
+	if ingress.Spec.DefaultBackend != nil {
+		backends = append(backends, *ingress.Spec.DefaultBackend)
+	}
+	if withRules {
+		for i := range ingress.Spec.Rules {
+			backends = append(backends, ingressRuleBackends(&ingress.Spec.Rules[i])...)
+		}
+	}
+	return backends
+}
+
+// ingressRuleBackends returns the backends of the paths of an ingress rule
+func ingressRuleBackends(rule *networkingv1.IngressRule) []networkingv1.IngressBackend {
+	if rule.HTTP == nil {
+		return nil
+	}
+	return lo.Map(rule.HTTP.Paths, func(path networkingv1.HTTPIngressPath, _ int) networkingv1.IngressBackend {
+		return path.Backend
+	})
+}
+
+// ingressServiceKeyFunc returns a function that returns the index key of the Service of an ingress backend.
+// Resource backends are disregarded.
+func ingressServiceKeyFunc(namespace string) func(backend networkingv1.IngressBackend, _ int) (string, bool) {
+	return func(backend networkingv1.IngressBackend, _ int) (string, bool) {
+		if backend.Service == nil {
+			return "", false
+		}
+		return backendKey(ServiceGroupKind.Group, ServiceGroupKind.Kind, namespace, backend.Service.Name), true
+	}
+}
+
+// ingressServicePortKeyFunc returns a function that returns the index key of the service port of an ingress backend,
+// by port number or port name. Resource backends are disregarded.
+func ingressServicePortKeyFunc(namespace string) func(backend networkingv1.IngressBackend, _ int) (string, bool) {
+	serviceKey := ingressServiceKeyFunc(namespace)
+	return func(backend networkingv1.IngressBackend, i int) (string, bool) {
+		key, ok := serviceKey(backend, i)
+		if !ok {
+			return "", false
+		}
+		if backend.Service.Port.Name != "" {
+			return namespacedSectionName(key, gwapiv1.SectionName(backend.Service.Port.Name)), true
+		}
+		return portKey(key, backend.Service.Port.Number), true
+	}
+}
+
+// ingressServicePortKeys returns the index keys of a service port, by port number and by port name
+func ingressServicePortKeys(child Object) []string {
+	servicePort := child.(*ServicePort)
+	key := serviceKey(servicePort.Service)
+	keys := []string{portKey(key, servicePort.Port)}
+	if servicePort.Name != "" {
+		keys = append(keys, namespacedSectionName(key, gwapiv1.SectionName(servicePort.Name)))
+	}
+	return keys
+}
@@ -0,0 +1,203 @@
+//go:build unit
+
+package machinery
+
+import (
+	"slices"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestIngressTopology(t *testing.T) {
+	ingressClasses := []*networkingv1.IngressClass{
+		BuildIngressClass(),
+		BuildIngressClass(func(ic *networkingv1.IngressClass) {
+			ic.Name = "default-ingress-class"
+			ic.Annotations = map[string]string{networkingv1.AnnotationIsDefaultIngressClass: "true"}
+		}),
+		BuildIngressClass(func(ic *networkingv1.IngressClass) { ic.Name = "legacy-ingress-class" }),
+	}
+	ingresses := []*networkingv1.Ingress{
+		BuildIngress(func(i *networkingv1.Ingress) {
+			i.Spec.DefaultBackend = &networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: "other-service", Port: networkingv1.ServiceBackendPort{Number: 8080}},
+			}
+			i.Spec.Rules[0].HTTP.Paths = append(i.Spec.Rules[0].HTTP.Paths, BuildIngressPath(func(p *networkingv1.HTTPIngressPath) {
+				p.Path = "/metrics"
+				p.Backend.Service.Port = networkingv1.ServiceBackendPort{Name: "metrics"}
+			}))
+			i.Spec.Rules = append(i.Spec.Rules, networkingv1.IngressRule{
+				Host: "other.example.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							BuildIngressPath(func(p *networkingv1.HTTPIngressPath) {
+								p.Backend.Service = &networkingv1.IngressServiceBackend{Name: "other-service", Port: networkingv1.ServiceBackendPort{Name: "grpc"}}
+							}),
+							BuildIngressPath(func(p *networkingv1.HTTPIngressPath) {
+								p.Path = "/static"
+								p.Backend = networkingv1.IngressBackend{Resource: &core.TypedLocalObjectReference{APIGroup: ptr.To("example.com"), Kind: "StorageBucket", Name: "static-assets"}}
+							}),
+						},
+					},
+				},
+			})
+		}),
+		BuildIngress(func(i *networkingv1.Ingress) {
+			i.Name = "classless-ingress"
+			i.Spec.IngressClassName = nil
+		}),
+		BuildIngress(func(i *networkingv1.Ingress) {
+			i.Name = "legacy-ingress"
+			i.Spec.IngressClassName = nil
+			i.Annotations = map[string]string{"kubernetes.io/ingress.class": "legacy-ingress-class"}
+			i.Spec.DefaultBackend = &networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{Name: "my-service", Port: networkingv1.ServiceBackendPort{Number: 80}},
+			}
+			i.Spec.Rules = nil
+		}),
+	}
+	services := []*core.Service{
+		BuildService(func(s *core.Service) {
+			s.Spec.Ports = append(s.Spec.Ports, core.ServicePort{Name: "metrics", Port: 9090})
+		}),
+		BuildService(func(s *core.Service) {
+			s.Name = "other-service"
+			s.Spec.Ports = []core.ServicePort{{Name: "grpc", Port: 8080}}
+		}),
+	}
+
+	testCases := []struct {
+		name          string
+		options       []IngressTopologyOptionsFunc
+		expectedLinks map[string][]string
+	}{
+		{
+			name: "ingresses",
+			expectedLinks: map[string][]string{
+				"my-namespace":          {"my-ingress", "classless-ingress", "legacy-ingress"},
+				"my-ingress-class":      {"my-ingress"},
+				"default-ingress-class": {"classless-ingress"},
+				"legacy-ingress-class":  {"legacy-ingress"},
+				"my-ingress":            {"my-service", "other-service"},
+				"classless-ingress":     {"my-service"},
+				"legacy-ingress":        {"my-service"},
+			},
+		},
+		{
+			name:    "ingress rules",
+			options: []IngressTopologyOptionsFunc{ExpandIngressRules()},
+			expectedLinks: map[string][]string{
+				"my-ingress":               {"other-service", "my-ingress#rule-1", "my-ingress#rule-2"},
+				"my-ingress#rule-1":        {"my-service"},
+				"my-ingress#rule-2":        {"other-service"},
+				"classless-ingress":        {"classless-ingress#rule-1"},
+				"classless-ingress#rule-1": {"my-service"},
+				"legacy-ingress":           {"my-service"},
+			},
+		},
+		{
+			name:    "ingress paths",
+			options: []IngressTopologyOptionsFunc{ExpandIngressPaths()},
+			expectedLinks: map[string][]string{
+				"my-ingress#rule-1":        {"my-ingress#rule-1.path-1", "my-ingress#rule-1.path-2"},
+				"my-ingress#rule-1.path-1": {"my-service"},
+				"my-ingress#rule-1.path-2": {"my-service"},
+				"my-ingress#rule-2":        {"my-ingress#rule-2.path-1", "my-ingress#rule-2.path-2"},
+				"my-ingress#rule-2.path-1": {"other-service"},
+				"my-ingress#rule-2.path-2": {},
+			},
+		},
+		{
+			name:    "service ports",
+			options: []IngressTopologyOptionsFunc{ExpandIngressServicePorts()},
+			expectedLinks: map[string][]string{
+				"my-ingress":     {"other-service#grpc", "my-service#http", "my-service#metrics"},
+				"legacy-ingress": {"my-service#http"},
+				"my-service":     {"my-service#http", "my-service#metrics"},
+				"other-service":  {"other-service#grpc"},
+			},
+		},
+		{
+			name:    "ingress paths and service ports",
+			options: []IngressTopologyOptionsFunc{ExpandIngressPaths(), ExpandIngressServicePorts()},
+			expectedLinks: map[string][]string{
+				"my-ingress":               {"other-service#grpc", "my-ingress#rule-1", "my-ingress#rule-2"},
+				"my-ingress#rule-1.path-1": {"my-service#http"},
+				"my-ingress#rule-1.path-2": {"my-service#metrics"},
+				"my-ingress#rule-2.path-1": {"other-service#grpc"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			topology, err := NewIngressTopology(append([]IngressTopologyOptionsFunc{
+				WithIngressTopologyNamespaces(BuildNamespace()),
+				WithIngressClasses(ingressClasses...),
+				WithIngresses(ingresses...),
+				WithIngressTopologyServices(services...),
+			}, tc.options...)...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			links := make(map[string][]string)
+			for _, root := range topology.Targetables().Roots() {
+				linksFromTargetable(topology, root, links)
+			}
+			for from, expectedTos := range tc.expectedLinks {
+				tos := links[from]
+				slices.Sort(expectedTos)
+				slices.Sort(tos)
+				if !slices.Equal(expectedTos, tos) {
+					t.Errorf("expected links from %s to be %v, got %v", from, expectedTos, tos)
+				}
+			}
+		})
+	}
+}
+
+func TestIngressTopologyWithPolicies(t *testing.T) {
+	ingressPolicy := buildPolicy(func(p *TestPolicy) {
+		p.Name = "ingress-policy"
+		p.Spec.TargetRef.Group = networkingv1.GroupName
+		p.Spec.TargetRef.Kind = "Ingress"
+		p.Spec.TargetRef.Name = "my-ingress"
+	})
+	pathPolicy := buildPolicy(func(p *TestPolicy) {
+		p.Name = "path-policy"
+		p.Spec.TargetRef.Group = networkingv1.GroupName
+		p.Spec.TargetRef.Kind = "Ingress"
+		p.Spec.TargetRef.Name = "my-ingress"
+		p.Spec.TargetRef.SectionName = ptr.To(gwapiv1.SectionName("rule-1.path-1"))
+	})
+
+	topology, err := NewIngressTopology(
+		WithIngressClasses(BuildIngressClass()),
+		WithIngresses(BuildIngress()),
+		WithIngressTopologyServices(BuildService()),
+		WithIngressTopologyPolicies(ingressPolicy, pathPolicy),
+		ExpandIngressPaths(),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	policies := make(map[string][]string)
+	for _, targetable := range topology.Targetables().Items() {
+		for _, policy := range targetable.Policies() {
+			policies[targetable.GetName()] = append(policies[targetable.GetName()], policy.GetName())
+		}
+	}
+	if !slices.Equal(policies["my-ingress"], []string{"ingress-policy"}) {
+		t.Errorf("expected the ingress policy attached to my-ingress, got %v", policies["my-ingress"])
+	}
+	if !slices.Equal(policies["my-ingress#rule-1.path-1"], []string{"path-policy"}) {
+		t.Errorf("expected the path policy attached to my-ingress#rule-1.path-1, got %v", policies["my-ingress#rule-1.path-1"])
+	}
+}
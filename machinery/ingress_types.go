@@ -0,0 +1,132 @@
+package machinery
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+var (
+	IngressClassGroupKind = networkingv1.SchemeGroupVersion.WithKind("IngressClass").GroupKind()
+	IngressGroupKind      = networkingv1.SchemeGroupVersion.WithKind("Ingress").GroupKind()
+	IngressRuleGroupKind  = networkingv1.SchemeGroupVersion.WithKind("IngressRule").GroupKind()
+	IngressPathGroupKind  = networkingv1.SchemeGroupVersion.WithKind("IngressPath").GroupKind()
+)
+
+// These are wrappers for Ingress API types so instances can be used as targetables in the topology.
+
+type IngressClass struct {
+	*networkingv1.IngressClass
+
+	attachedPolicies []Policy
+}
+
+var _ Targetable = &IngressClass{}
+
+func (c *IngressClass) GetLocator() string {
+	return LocatorFromObject(c)
+}
+
+func (c *IngressClass) SetPolicies(policies []Policy) {
+	c.attachedPolicies = policies
+}
+
+func (c *IngressClass) Policies() []Policy {
+	return c.attachedPolicies
+}
+
+type Ingress struct {
+	*networkingv1.Ingress
+
+	attachedPolicies []Policy
+}
+
+var _ Targetable = &Ingress{}
+
+func (i *Ingress) GetLocator() string {
+	return LocatorFromObject(i)
+}
+
+func (i *Ingress) SetPolicies(policies []Policy) {
+	i.attachedPolicies = policies
+}
+
+func (i *Ingress) Policies() []Policy {
+	return i.attachedPolicies
+}
+
+// IngressRule is a rule of an Ingress. Ingress rules have no name; they are named after their position in the list of
+// rules of the Ingress, e.g. `rule-1`.
+type IngressRule struct {
+	*networkingv1.IngressRule
+
+	Ingress          *Ingress
+	Name             gwapiv1.SectionName
+	attachedPolicies []Policy
+}
+
+var _ Targetable = &IngressRule{}
+
+func (r *IngressRule) GroupVersionKind() schema.GroupVersionKind {
+	return networkingv1.SchemeGroupVersion.WithKind("IngressRule")
+}
+
+func (r *IngressRule) SetGroupVersionKind(schema.GroupVersionKind) {}
+
+func (r *IngressRule) GetLocator() string {
+	return namespacedSectionName(LocatorFromObject(r.Ingress), r.Name)
+}
+
+func (r *IngressRule) GetNamespace() string {
+	return r.Ingress.GetNamespace()
+}
+
+func (r *IngressRule) GetName() string {
+	return namespacedSectionName(r.Ingress.Name, r.Name)
+}
+
+func (r *IngressRule) SetPolicies(policies []Policy) {
+	r.attachedPolicies = policies
+}
+
+func (r *IngressRule) Policies() []Policy {
+	return r.attachedPolicies
+}
+
+// IngressPath is an HTTP path of a rule of an Ingress. Ingress paths have no name; they are named after the rule and
+// their position in the list of paths of the rule, e.g. `rule-1.path-2`.
+type IngressPath struct {
+	*networkingv1.HTTPIngressPath
+
+	IngressRule      *IngressRule
+	Name             gwapiv1.SectionName
+	attachedPolicies []Policy
+}
+
+var _ Targetable = &IngressPath{}
+
+func (p *IngressPath) GroupVersionKind() schema.GroupVersionKind {
+	return networkingv1.SchemeGroupVersion.WithKind("IngressPath")
+}
+
+func (p *IngressPath) SetGroupVersionKind(schema.GroupVersionKind) {}
+
+func (p *IngressPath) GetLocator() string {
+	return namespacedSectionName(LocatorFromObject(p.IngressRule.Ingress), p.Name)
+}
+
+func (p *IngressPath) GetNamespace() string {
+	return p.IngressRule.GetNamespace()
+}
+
+func (p *IngressPath) GetName() string {
+	return namespacedSectionName(p.IngressRule.Ingress.Name, p.Name)
+}
+
+func (p *IngressPath) SetPolicies(policies []Policy) {
+	p.attachedPolicies = policies
+}
+
+func (p *IngressPath) Policies() []Policy {
+	return p.attachedPolicies
+}
@@ -0,0 +1,361 @@
+// Package machinerytest provides generators of synthetic Gateway API topologies, for writing scale and load tests and
+// benchmarks of the machinery.
+package machinerytest
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/samber/lo"
+	core "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kuadrant/policy-machinery/machinery"
+)
+
+// AttachmentDistribution tells how references are distributed among the candidate referents.
+type AttachmentDistribution string
+
+const (
+	// UniformDistribution picks every candidate referent with the same probability.
+	UniformDistribution AttachmentDistribution = "Uniform"
+	// ZipfDistribution picks the first candidate referents much more often than the last ones, resulting in a few
+	// "hot" referents with many attachments and a long tail of referents with few attachments.
+	ZipfDistribution AttachmentDistribution = "Zipf"
+)
+
+// GatewayAPITopologySpec describes the shape of a synthetic Gateway API topology.
+// Objects are spread evenly across the namespaces. The same spec and seed always produce the same objects.
+type GatewayAPITopologySpec struct {
+	// Seed of the pseudo-random generator
+	Seed uint64
+
+	Namespaces          int
+	GatewayClasses      int
+	Gateways            int
+	ListenersPerGateway int
+	HTTPRoutes          int
+	GRPCRoutes          int
+	RulesPerRoute       int
+	Services            int
+	PortsPerService     int
+	Policies            int
+
+	// ParentRefsPerRoute is the number of Gateways each route attaches to
+	ParentRefsPerRoute int
+	// BackendRefsPerRule is the number of Services each route rule routes to
+	BackendRefsPerRule int
+	// SectionNameRatio is the fraction of parentRefs and backendRefs that specify a listener or a port
+	SectionNameRatio float64
+	// CrossNamespaceRatio is the fraction of parentRefs and backendRefs to objects in another namespace
+	CrossNamespaceRatio float64
+	// Attachment is the distribution of parentRefs, backendRefs and policy targetRefs among the referents
+	Attachment AttachmentDistribution
+}
+
+// DefaultGatewayAPITopologySpec returns a spec for a small topology with one of each kind of object per namespace.
+func DefaultGatewayAPITopologySpec() GatewayAPITopologySpec {
+	return GatewayAPITopologySpec{
+		Seed:                1,
+		Namespaces:          1,
+		GatewayClasses:      1,
+		Gateways:            1,
+		ListenersPerGateway: 1,
+		HTTPRoutes:          1,
+		GRPCRoutes:          1,
+		RulesPerRoute:       1,
+		Services:            1,
+		PortsPerService:     1,
+		Policies:            1,
+		ParentRefsPerRoute:  1,
+		BackendRefsPerRule:  1,
+		Attachment:          UniformDistribution,
+	}
+}
+
+// GeneratedGatewayAPITopology holds the raw objects generated from a GatewayAPITopologySpec and the topology built
+// out of them.
+type GeneratedGatewayAPITopology struct {
+	Namespaces     []string
+	GatewayClasses []*gwapiv1.GatewayClass
+	Gateways       []*gwapiv1.Gateway
+	HTTPRoutes     []*gwapiv1.HTTPRoute
+	GRPCRoutes     []*gwapiv1.GRPCRoute
+	Services       []*core.Service
+	Policies       []*Policy
+
+	Topology *machinery.Topology
+}
+
+// GenerateGatewayAPITopology generates the objects described by the spec and builds a Gateway API topology out of
+// them, with Gateway listeners, route rules and service ports expanded.
+// Additional options to build the topology can be supplied; they are applied after the ones set by the generator.
+func GenerateGatewayAPITopology(spec GatewayAPITopologySpec, options ...machinery.GatewayAPITopologyOptionsFunc) (*GeneratedGatewayAPITopology, error) {
+	g := GenerateGatewayAPIObjects(spec)
+
+	opts := []machinery.GatewayAPITopologyOptionsFunc{
+		machinery.WithGatewayClasses(g.GatewayClasses...),
+		machinery.WithGateways(g.Gateways...),
+		machinery.WithHTTPRoutes(g.HTTPRoutes...),
+		machinery.WithGRPCRoutes(g.GRPCRoutes...),
+		machinery.WithServices(g.Services...),
+		machinery.WithGatewayAPITopologyPolicies(lo.Map(g.Policies, func(p *Policy, _ int) machinery.Policy { return p })...),
+		machinery.ExpandGatewayListeners(),
+		machinery.ExpandHTTPRouteRules(),
+		machinery.ExpandGRPCRouteRules(),
+		machinery.ExpandServicePorts(),
+	}
+
+	topology, err := machinery.NewGatewayAPITopology(append(opts, options...)...)
+	g.Topology = topology
+	return g, err
+}
+
+// GenerateGatewayAPIObjects generates the objects described by the spec, without building the topology.
+func GenerateGatewayAPIObjects(spec GatewayAPITopologySpec) *GeneratedGatewayAPITopology {
+	gen := &generator{
+		spec: spec,
+		rand: rand.New(rand.NewPCG(spec.Seed, spec.Seed)),
+		zipf: make(map[int]*rand.Zipf),
+	}
+	g := &GeneratedGatewayAPITopology{}
+
+	g.Namespaces = lo.Times(max(spec.Namespaces, 1), func(i int) string {
+		return fmt.Sprintf("namespace-%d", i)
+	})
+
+	g.GatewayClasses = lo.Times(spec.GatewayClasses, func(i int) *gwapiv1.GatewayClass {
+		return &gwapiv1.GatewayClass{
+			TypeMeta:   metav1.TypeMeta{APIVersion: gwapiv1.GroupVersion.String(), Kind: machinery.GatewayClassGroupKind.Kind},
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("gatewayclass-%d", i)},
+			Spec:       gwapiv1.GatewayClassSpec{ControllerName: "example.com/gateway-controller"},
+		}
+	})
+
+	g.Gateways = lo.Times(spec.Gateways, func(i int) *gwapiv1.Gateway {
+		gateway := &gwapiv1.Gateway{
+			TypeMeta:   metav1.TypeMeta{APIVersion: gwapiv1.GroupVersion.String(), Kind: machinery.GatewayGroupKind.Kind},
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("gateway-%d", i), Namespace: g.Namespaces[i%len(g.Namespaces)]},
+			Spec: gwapiv1.GatewaySpec{
+				Listeners: lo.Times(max(spec.ListenersPerGateway, 1), func(j int) gwapiv1.Listener {
+					return gwapiv1.Listener{
+						Name:     gwapiv1.SectionName(fmt.Sprintf("listener-%d", j)),
+						Port:     gwapiv1.PortNumber(8000 + j),
+						Protocol: gwapiv1.HTTPProtocolType,
+					}
+				}),
+			},
+		}
+		if spec.GatewayClasses > 0 {
+			gateway.Spec.GatewayClassName = gwapiv1.ObjectName(g.GatewayClasses[gen.pick(spec.GatewayClasses)].Name)
+		}
+		return gateway
+	})
+
+	g.Services = lo.Times(spec.Services, func(i int) *core.Service {
+		return &core.Service{
+			TypeMeta:   metav1.TypeMeta{APIVersion: core.SchemeGroupVersion.String(), Kind: machinery.ServiceGroupKind.Kind},
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("service-%d", i), Namespace: g.Namespaces[i%len(g.Namespaces)]},
+			Spec: core.ServiceSpec{
+				Ports: lo.Times(max(spec.PortsPerService, 1), func(j int) core.ServicePort {
+					return core.ServicePort{
+						Name: fmt.Sprintf("port-%d", j),
+						Port: int32(80 + j),
+					}
+				}),
+			},
+		}
+	})
+
+	gateways := newCandidates(g.Gateways)
+	services := newCandidates(g.Services)
+
+	g.HTTPRoutes = lo.Times(spec.HTTPRoutes, func(i int) *gwapiv1.HTTPRoute {
+		namespace := g.Namespaces[i%len(g.Namespaces)]
+		return &gwapiv1.HTTPRoute{
+			TypeMeta:   metav1.TypeMeta{APIVersion: gwapiv1.GroupVersion.String(), Kind: machinery.HTTPRouteGroupKind.Kind},
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("http-route-%d", i), Namespace: namespace},
+			Spec: gwapiv1.HTTPRouteSpec{
+				CommonRouteSpec: gwapiv1.CommonRouteSpec{ParentRefs: gen.parentRefs(namespace, gateways)},
+				Rules: lo.Times(max(spec.RulesPerRoute, 1), func(_ int) gwapiv1.HTTPRouteRule {
+					return gwapiv1.HTTPRouteRule{
+						BackendRefs: lo.Map(gen.backendRefs(namespace, services), func(backendRef gwapiv1.BackendRef, _ int) gwapiv1.HTTPBackendRef {
+							return gwapiv1.HTTPBackendRef{BackendRef: backendRef}
+						}),
+					}
+				}),
+			},
+		}
+	})
+
+	g.GRPCRoutes = lo.Times(spec.GRPCRoutes, func(i int) *gwapiv1.GRPCRoute {
+		namespace := g.Namespaces[i%len(g.Namespaces)]
+		return &gwapiv1.GRPCRoute{
+			TypeMeta:   metav1.TypeMeta{APIVersion: gwapiv1.GroupVersion.String(), Kind: machinery.GRPCRouteGroupKind.Kind},
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("grpc-route-%d", i), Namespace: namespace},
+			Spec: gwapiv1.GRPCRouteSpec{
+				CommonRouteSpec: gwapiv1.CommonRouteSpec{ParentRefs: gen.parentRefs(namespace, gateways)},
+				Rules: lo.Times(max(spec.RulesPerRoute, 1), func(_ int) gwapiv1.GRPCRouteRule {
+					return gwapiv1.GRPCRouteRule{
+						BackendRefs: lo.Map(gen.backendRefs(namespace, services), func(backendRef gwapiv1.BackendRef, _ int) gwapiv1.GRPCBackendRef {
+							return gwapiv1.GRPCBackendRef{BackendRef: backendRef}
+						}),
+					}
+				}),
+			},
+		}
+	})
+
+	g.Policies = gen.policies(g)
+
+	return g
+}
+
+type generator struct {
+	spec GatewayAPITopologySpec
+	rand *rand.Rand
+	zipf map[int]*rand.Zipf
+}
+
+// pick returns the index of one of n candidates, according to the attachment distribution of the spec
+func (gen *generator) pick(n int) int {
+	if n <= 1 {
+		return 0
+	}
+	if gen.spec.Attachment == ZipfDistribution {
+		zipf, ok := gen.zipf[n]
+		if !ok {
+			zipf = rand.NewZipf(gen.rand, 1.1, 1, uint64(n-1))
+			gen.zipf[n] = zipf
+		}
+		return int(zipf.Uint64())
+	}
+	return gen.rand.IntN(n)
+}
+
+// chance returns true with the given probability
+func (gen *generator) chance(probability float64) bool {
+	return probability > 0 && gen.rand.Float64() < probability
+}
+
+// candidates are referents grouped by namespace, in the order they were generated
+type candidates struct {
+	all         []metav1.Object
+	namespaces  []string
+	byNamespace map[string][]metav1.Object
+}
+
+func newCandidates[T metav1.Object](objects []T) *candidates {
+	c := &candidates{byNamespace: make(map[string][]metav1.Object)}
+	for _, obj := range objects {
+		namespace := obj.GetNamespace()
+		if _, ok := c.byNamespace[namespace]; !ok {
+			c.namespaces = append(c.namespaces, namespace)
+		}
+		c.byNamespace[namespace] = append(c.byNamespace[namespace], obj)
+		c.all = append(c.all, obj)
+	}
+	return c
+}
+
+// pickObject returns one of the candidate objects, either from the given namespace or from another namespace,
+// according to the cross-namespace ratio of the spec.
+// It falls back to any candidate if there are no candidates in the preferred namespaces.
+func (gen *generator) pickObject(namespace string, c *candidates) metav1.Object {
+	if gen.chance(gen.spec.CrossNamespaceRatio) {
+		if others := lo.Without(c.namespaces, namespace); len(others) > 0 {
+			objects := c.byNamespace[others[gen.rand.IntN(len(others))]]
+			return objects[gen.pick(len(objects))]
+		}
+	} else if objects := c.byNamespace[namespace]; len(objects) > 0 {
+		return objects[gen.pick(len(objects))]
+	}
+	return c.all[gen.pick(len(c.all))]
+}
+
+func (gen *generator) parentRefs(namespace string, gateways *candidates) []gwapiv1.ParentReference {
+	if len(gateways.all) == 0 {
+		return nil
+	}
+	return lo.Times(max(gen.spec.ParentRefsPerRoute, 1), func(_ int) gwapiv1.ParentReference {
+		gateway := gen.pickObject(namespace, gateways)
+		parentRef := gwapiv1.ParentReference{Name: gwapiv1.ObjectName(gateway.GetName())}
+		if gateway.GetNamespace() != namespace {
+			parentRef.Namespace = ptr.To(gwapiv1.Namespace(gateway.GetNamespace()))
+		}
+		if gen.chance(gen.spec.SectionNameRatio) {
+			listeners := gateway.(*gwapiv1.Gateway).Spec.Listeners
+			parentRef.SectionName = ptr.To(listeners[gen.pick(len(listeners))].Name)
+		}
+		return parentRef
+	})
+}
+
+func (gen *generator) backendRefs(namespace string, services *candidates) []gwapiv1.BackendRef {
+	if len(services.all) == 0 {
+		return nil
+	}
+	return lo.Times(max(gen.spec.BackendRefsPerRule, 1), func(_ int) gwapiv1.BackendRef {
+		service := gen.pickObject(namespace, services)
+		backendRef := gwapiv1.BackendRef{
+			BackendObjectReference: gwapiv1.BackendObjectReference{Name: gwapiv1.ObjectName(service.GetName())},
+		}
+		if service.GetNamespace() != namespace {
+			backendRef.Namespace = ptr.To(gwapiv1.Namespace(service.GetNamespace()))
+		}
+		if gen.chance(gen.spec.SectionNameRatio) {
+			ports := service.(*core.Service).Spec.Ports
+			backendRef.Port = ptr.To(gwapiv1.PortNumber(ports[gen.pick(len(ports))].Port))
+		}
+		return backendRef
+	})
+}
+
+// policies generates policies that target the generated objects, including their sections
+func (gen *generator) policies(g *GeneratedGatewayAPITopology) []*Policy {
+	type target struct {
+		kind        string
+		namespace   string
+		name        string
+		sectionName *gwapiv1.SectionName
+	}
+	var targets []target
+	for _, gateway := range g.Gateways {
+		targets = append(targets, target{kind: machinery.GatewayGroupKind.Kind, namespace: gateway.Namespace, name: gateway.Name})
+		for _, listener := range gateway.Spec.Listeners {
+			targets = append(targets, target{kind: machinery.GatewayGroupKind.Kind, namespace: gateway.Namespace, name: gateway.Name, sectionName: ptr.To(listener.Name)})
+		}
+	}
+	for _, httpRoute := range g.HTTPRoutes {
+		targets = append(targets, target{kind: machinery.HTTPRouteGroupKind.Kind, namespace: httpRoute.Namespace, name: httpRoute.Name})
+		for i := range httpRoute.Spec.Rules {
+			targets = append(targets, target{kind: machinery.HTTPRouteGroupKind.Kind, namespace: httpRoute.Namespace, name: httpRoute.Name, sectionName: ptr.To(gwapiv1.SectionName(fmt.Sprintf("rule-%d", i+1)))})
+		}
+	}
+	for _, grpcRoute := range g.GRPCRoutes {
+		targets = append(targets, target{kind: machinery.GRPCRouteGroupKind.Kind, namespace: grpcRoute.Namespace, name: grpcRoute.Name})
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	return lo.Times(gen.spec.Policies, func(i int) *Policy {
+		t := targets[gen.pick(len(targets))]
+		return &Policy{
+			TypeMeta:   metav1.TypeMeta{APIVersion: PolicyGroupVersion.String(), Kind: PolicyKind},
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("policy-%d", i), Namespace: t.namespace},
+			Spec: PolicySpec{
+				TargetRef: gwapiv1.LocalPolicyTargetReferenceWithSectionName{
+					LocalPolicyTargetReference: gwapiv1.LocalPolicyTargetReference{
+						Group: gwapiv1.GroupName,
+						Kind:  gwapiv1.Kind(t.kind),
+						Name:  gwapiv1.ObjectName(t.name),
+					},
+					SectionName: t.sectionName,
+				},
+			},
+		}
+	})
+}
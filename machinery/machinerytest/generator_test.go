@@ -0,0 +1,206 @@
+//go:build unit
+
+package machinerytest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/samber/lo"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kuadrant/policy-machinery/machinery"
+)
+
+func TestGenerateGatewayAPITopology(t *testing.T) {
+	spec := GatewayAPITopologySpec{
+		Seed:                42,
+		Namespaces:          3,
+		GatewayClasses:      2,
+		Gateways:            6,
+		ListenersPerGateway: 2,
+		HTTPRoutes:          20,
+		GRPCRoutes:          10,
+		RulesPerRoute:       2,
+		Services:            15,
+		PortsPerService:     2,
+		Policies:            25,
+		ParentRefsPerRoute:  2,
+		BackendRefsPerRule:  2,
+		SectionNameRatio:    0.5,
+		CrossNamespaceRatio: 0.2,
+		Attachment:          ZipfDistribution,
+	}
+
+	g, err := GenerateGatewayAPITopology(spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if expected := spec.Namespaces; len(g.Namespaces) != expected {
+		t.Errorf("expected %d namespaces, got %d", expected, len(g.Namespaces))
+	}
+	if expected := spec.GatewayClasses; len(g.GatewayClasses) != expected {
+		t.Errorf("expected %d gateway classes, got %d", expected, len(g.GatewayClasses))
+	}
+	if expected := spec.Gateways; len(g.Gateways) != expected {
+		t.Errorf("expected %d gateways, got %d", expected, len(g.Gateways))
+	}
+	if expected := spec.HTTPRoutes; len(g.HTTPRoutes) != expected {
+		t.Errorf("expected %d http routes, got %d", expected, len(g.HTTPRoutes))
+	}
+	if expected := spec.GRPCRoutes; len(g.GRPCRoutes) != expected {
+		t.Errorf("expected %d grpc routes, got %d", expected, len(g.GRPCRoutes))
+	}
+	if expected := spec.Services; len(g.Services) != expected {
+		t.Errorf("expected %d services, got %d", expected, len(g.Services))
+	}
+	if expected := spec.Policies; len(g.Policies) != expected {
+		t.Errorf("expected %d policies, got %d", expected, len(g.Policies))
+	}
+
+	for _, route := range g.HTTPRoutes {
+		if expected := spec.ParentRefsPerRoute; len(route.Spec.ParentRefs) != expected {
+			t.Errorf("expected %d parentRefs in http route %s, got %d", expected, route.Name, len(route.Spec.ParentRefs))
+		}
+		if expected := spec.RulesPerRoute; len(route.Spec.Rules) != expected {
+			t.Errorf("expected %d rules in http route %s, got %d", expected, route.Name, len(route.Spec.Rules))
+		}
+		for _, rule := range route.Spec.Rules {
+			if expected := spec.BackendRefsPerRule; len(rule.BackendRefs) != expected {
+				t.Errorf("expected %d backendRefs in rule of http route %s, got %d", expected, route.Name, len(rule.BackendRefs))
+			}
+		}
+	}
+
+	// gateways, listeners, http routes, http route rules, grpc routes, grpc route rules, services, service ports
+	expectedTargetables := spec.GatewayClasses +
+		spec.Gateways*(1+spec.ListenersPerGateway) +
+		spec.HTTPRoutes*(1+spec.RulesPerRoute) +
+		spec.GRPCRoutes*(1+spec.RulesPerRoute) +
+		spec.Services*(1+spec.PortsPerService)
+	if targetables := g.Topology.Targetables().Items(); len(targetables) != expectedTargetables {
+		t.Errorf("expected %d targetables, got %d", expectedTargetables, len(targetables))
+	}
+	if policies := g.Topology.Policies().Items(); len(policies) != spec.Policies {
+		t.Errorf("expected %d policies in the topology, got %d", spec.Policies, len(policies))
+	}
+}
+
+func TestGenerateGatewayAPITopologyIsDeterministic(t *testing.T) {
+	spec := DefaultGatewayAPITopologySpec()
+	spec.Namespaces = 2
+	spec.Gateways = 4
+	spec.HTTPRoutes = 10
+	spec.Services = 10
+	spec.Policies = 10
+	spec.SectionNameRatio = 0.5
+	spec.CrossNamespaceRatio = 0.5
+	spec.Attachment = ZipfDistribution
+
+	g1, err := GenerateGatewayAPITopology(spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	g2, err := GenerateGatewayAPITopology(spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g1.Topology.ToDot() != g2.Topology.ToDot() {
+		t.Errorf("expected the same topology to be generated from the same spec")
+	}
+
+	spec.Seed++
+	g3, err := GenerateGatewayAPITopology(spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if g1.Topology.ToDot() == g3.Topology.ToDot() {
+		t.Errorf("expected a different topology to be generated from a different seed")
+	}
+}
+
+func TestGenerateGatewayAPIObjectsCrossNamespaceRatio(t *testing.T) {
+	testCases := []struct {
+		name                string
+		crossNamespaceRatio float64
+		expectCrossRefs     bool
+	}{
+		{name: "same namespace", crossNamespaceRatio: 0, expectCrossRefs: false},
+		{name: "cross namespace", crossNamespaceRatio: 1, expectCrossRefs: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec := DefaultGatewayAPITopologySpec()
+			spec.Namespaces = 3
+			spec.Gateways = 3
+			spec.HTTPRoutes = 30
+			spec.Services = 3
+			spec.CrossNamespaceRatio = tc.crossNamespaceRatio
+
+			g := GenerateGatewayAPIObjects(spec)
+
+			for _, route := range g.HTTPRoutes {
+				for _, parentRef := range route.Spec.ParentRefs {
+					if crossRef := parentRef.Namespace != nil && string(*parentRef.Namespace) != route.Namespace; crossRef != tc.expectCrossRefs {
+						t.Errorf("http route %s: expected cross-namespace parentRef to be %v, got %v", route.Name, tc.expectCrossRefs, crossRef)
+					}
+				}
+				for _, backendRef := range route.Spec.Rules[0].BackendRefs {
+					if crossRef := backendRef.Namespace != nil && string(*backendRef.Namespace) != route.Namespace; crossRef != tc.expectCrossRefs {
+						t.Errorf("http route %s: expected cross-namespace backendRef to be %v, got %v", route.Name, tc.expectCrossRefs, crossRef)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateGatewayAPIObjectsZipfDistribution(t *testing.T) {
+	spec := DefaultGatewayAPITopologySpec()
+	spec.Gateways = 20
+	spec.HTTPRoutes = 1000
+	spec.Services = 1
+
+	attachments := func(routes []*gwapiv1.HTTPRoute) map[string]int {
+		return lo.CountValuesBy(routes, func(route *gwapiv1.HTTPRoute) string {
+			return string(route.Spec.ParentRefs[0].Name)
+		})
+	}
+
+	spec.Attachment = UniformDistribution
+	uniform := attachments(GenerateGatewayAPIObjects(spec).HTTPRoutes)
+
+	spec.Attachment = ZipfDistribution
+	zipf := attachments(GenerateGatewayAPIObjects(spec).HTTPRoutes)
+
+	if hottest := lo.Max(lo.Values(zipf)); hottest <= lo.Max(lo.Values(uniform)) {
+		t.Errorf("expected the zipf distribution to concentrate more attachments in a single gateway than the uniform distribution, got %d", hottest)
+	}
+	if zipf["gateway-0"] < zipf["gateway-19"] {
+		t.Errorf("expected the first gateway to be attached more often than the last one with the zipf distribution, got %d < %d", zipf["gateway-0"], zipf["gateway-19"])
+	}
+}
+
+func BenchmarkGenerateGatewayAPITopology(b *testing.B) {
+	for _, size := range []int{100, 1000} {
+		spec := DefaultGatewayAPITopologySpec()
+		spec.Namespaces = 10
+		spec.Gateways = size / 100
+		spec.HTTPRoutes = size
+		spec.Services = size
+		spec.Policies = size / 10
+		spec.SectionNameRatio = 0.2
+		spec.CrossNamespaceRatio = 0.1
+		spec.Attachment = ZipfDistribution
+
+		b.Run(fmt.Sprintf("routes=%d", size), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := GenerateGatewayAPITopology(spec, machinery.WithGatewayAPITopologyParallelism(4)); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
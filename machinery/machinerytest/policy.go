@@ -0,0 +1,55 @@
+package machinerytest
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kuadrant/policy-machinery/machinery"
+)
+
+const PolicyKind = "GeneratedPolicy"
+
+var (
+	PolicyGroupVersion = schema.GroupVersion{Group: "machinerytest.kuadrant.io", Version: "v1"}
+	PolicyGroupKind    = PolicyGroupVersion.WithKind(PolicyKind).GroupKind()
+)
+
+// Policy is a policy kind generated for synthetic topologies, that targets a single object with optional section name.
+type Policy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PolicySpec `json:"spec"`
+}
+
+type PolicySpec struct {
+	TargetRef gwapiv1.LocalPolicyTargetReferenceWithSectionName `json:"targetRef"`
+}
+
+var _ machinery.Policy = &Policy{}
+
+func (p *Policy) GetLocator() string {
+	return machinery.LocatorFromObject(p)
+}
+
+func (p *Policy) GetTargetRefs() []machinery.PolicyTargetReference {
+	return []machinery.PolicyTargetReference{
+		machinery.LocalPolicyTargetReferenceWithSectionName{
+			LocalPolicyTargetReferenceWithSectionName: p.Spec.TargetRef,
+			PolicyNamespace: p.Namespace,
+		},
+	}
+}
+
+func (p *Policy) GetMergeStrategy() machinery.MergeStrategy {
+	return machinery.DefaultMergeStrategy
+}
+
+func (p *Policy) Merge(other machinery.Policy) machinery.Policy {
+	source, ok := other.(*Policy)
+	if !ok {
+		return p
+	}
+	return source.GetMergeStrategy()(source, p)
+}
@@ -0,0 +1,307 @@
+package machinery
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+const (
+	// PolicyConditionResolvedRefs indicates whether the controller was able to resolve all the object references of the
+	// policy other than the target references, e.g. Secrets.
+	PolicyConditionResolvedRefs gwapiv1.PolicyConditionType = "ResolvedRefs"
+
+	// PolicyConditionConflicted indicates that the policy conflicts with another policy of the same kind that targets
+	// the same targetable and takes precedence.
+	PolicyConditionConflicted gwapiv1.PolicyConditionType = "Conflicted"
+
+	PolicyReasonResolvedRefs gwapiv1.PolicyConditionReason = "ResolvedRefs"
+	PolicyReasonInvalidRef   gwapiv1.PolicyConditionReason = "InvalidRef"
+	PolicyReasonNoConflicts  gwapiv1.PolicyConditionReason = "NoConflicts"
+
+	// maxPolicyAncestors is the maximum number of entries in the `status.ancestors` field of a policy
+	maxPolicyAncestors = 16
+)
+
+type PolicyStatusOptions struct {
+	CurrentStatus   *gwapiv1.PolicyStatus
+	DetectConflicts bool
+	RefsResolver    func(policy Policy, topology *Topology) error
+}
+
+type PolicyStatusOptionsFunc func(*PolicyStatusOptions)
+
+// WithCurrentPolicyStatus sets the current status of the policy, so the entries of other controllers are preserved and
+// the conditions of the entries of the controller are merged into the existing ones, keeping their transition times
+// and condition types set by others.
+func WithCurrentPolicyStatus(status gwapiv1.PolicyStatus) PolicyStatusOptionsFunc {
+	return func(o *PolicyStatusOptions) {
+		o.CurrentStatus = &status
+	}
+}
+
+// DetectPolicyConflicts adds the `Conflicted` condition to the status of the policy.
+// A policy conflicts with the other policies of the same kind attached to the same targetable, unless it is the oldest
+// one, by creation timestamp and then by locator. Only enable it for kinds of policies that cannot be merged.
+func DetectPolicyConflicts() PolicyStatusOptionsFunc {
+	return func(o *PolicyStatusOptions) {
+		o.DetectConflicts = true
+	}
+}
+
+// WithPolicyRefsResolver adds the `ResolvedRefs` condition to the status of the policy, set to False with the error
+// returned by the given function, when the function fails to resolve the object references of the policy.
+func WithPolicyRefsResolver(resolver func(policy Policy, topology *Topology) error) PolicyStatusOptionsFunc {
+	return func(o *PolicyStatusOptions) {
+		o.RefsResolver = resolver
+	}
+}
+
+// PolicyStatusFromTopology returns the status of a policy per ancestor, as reported by the controller with the given
+// name, ready to be patched into the `status` field of the policy.
+//
+// The ancestors are the Gateways at or above the targetables the policy targets in the topology. Target references
+// with no Gateway above are reported with the target reference itself as the ancestor.
+// The `Accepted` condition of each ancestor is set based on the analysis of the topology:
+//   - TargetNotFound: the targetable referred does not exist in the topology;
+//   - Invalid: the attachment was refused by the topology (see PolicyWithAllowedTargets), or the references of the
+//     policy could not be resolved (see WithPolicyRefsResolver);
+//   - Conflicted: another policy takes precedence over the targetable (see DetectPolicyConflicts);
+//   - Accepted: otherwise.
+//
+// An ancestor of multiple target references is reported as accepted if the policy is accepted for any of them.
+// Up to 16 ancestors are reported, including the ones of other controllers in the current status, if supplied.
+func PolicyStatusFromTopology(topology *Topology, policy Policy, controllerName gwapiv1.GatewayController, options ...PolicyStatusOptionsFunc) gwapiv1.PolicyStatus {
+	o := &PolicyStatusOptions{}
+	for _, f := range options {
+		f(o)
+	}
+
+	var generation int64
+	if p, ok := policy.(interface{ GetGeneration() int64 }); ok {
+		generation = p.GetGeneration()
+	}
+
+	var refsErr error
+	if o.RefsResolver != nil {
+		refsErr = o.RefsResolver(policy, topology)
+	}
+
+	refusedPolicyAttachments := lo.Filter(topology.RefusedPolicyAttachments(), func(refused RefusedPolicyAttachment, _ int) bool {
+		return refused.Policy.GetLocator() == policy.GetLocator()
+	})
+
+	var ancestors []gwapiv1.ParentReference
+	conditionsByAncestor := make(map[string][]metav1.Condition)
+	for _, targetRef := range policy.GetTargetRefs() {
+		targetable := topology.targetables[targetRef.GetLocator()]
+
+		refused, isRefused := lo.Find(refusedPolicyAttachments, func(refused RefusedPolicyAttachment) bool {
+			return refused.TargetRef.GetLocator() == targetRef.GetLocator()
+		})
+
+		conditions := policyConditions(generation, refsErr, o.RefsResolver != nil, o.DetectConflicts)
+		accepted := meta.FindStatusCondition(conditions, string(gwapiv1.PolicyConditionAccepted))
+		switch {
+		case targetable == nil:
+			setPolicyNotAccepted(accepted, gwapiv1.PolicyReasonTargetNotFound, fmt.Sprintf("target %s not found", targetRef.GetLocator()))
+		case isRefused:
+			setPolicyNotAccepted(accepted, gwapiv1.PolicyReasonInvalid, refused.Reason)
+		case refsErr != nil:
+			setPolicyNotAccepted(accepted, gwapiv1.PolicyReasonInvalid, refsErr.Error())
+		}
+		if o.DetectConflicts && targetable != nil {
+			if precedent, conflicted := precedentPolicy(policy, targetable); conflicted {
+				conflictedCondition := meta.FindStatusCondition(conditions, string(PolicyConditionConflicted))
+				conflictedCondition.Status = metav1.ConditionTrue
+				conflictedCondition.Reason = string(gwapiv1.PolicyReasonConflicted)
+				conflictedCondition.Message = fmt.Sprintf("conflicts with %s on %s", precedent.GetLocator(), targetable.GetLocator())
+				if accepted.Status == metav1.ConditionTrue {
+					setPolicyNotAccepted(accepted, gwapiv1.PolicyReasonConflicted, conflictedCondition.Message)
+				}
+			}
+		}
+
+		var ancestorRefs []gwapiv1.ParentReference
+		if targetable != nil {
+			ancestorRefs = lo.Map(gatewayAncestors(topology, targetable), func(gateway Targetable, _ int) gwapiv1.ParentReference {
+				return ancestorRefFromObject(gateway)
+			})
+		}
+		if len(ancestorRefs) == 0 {
+			ancestorRefs = []gwapiv1.ParentReference{ancestorRefFromTargetRef(targetRef)}
+		}
+		for _, ancestorRef := range ancestorRefs {
+			key := ancestorRefKey(ancestorRef)
+			current, found := conditionsByAncestor[key]
+			if !found {
+				ancestors = append(ancestors, ancestorRef)
+			}
+			if !found || (!meta.IsStatusConditionTrue(current, string(gwapiv1.PolicyConditionAccepted)) && accepted.Status == metav1.ConditionTrue) {
+				conditionsByAncestor[key] = conditions
+			}
+		}
+	}
+
+	var status gwapiv1.PolicyStatus
+	var currentAncestors []gwapiv1.PolicyAncestorStatus
+	if o.CurrentStatus != nil {
+		currentAncestors = o.CurrentStatus.Ancestors
+	}
+	// keep the entries of other controllers
+	status.Ancestors = lo.Filter(currentAncestors, func(ancestor gwapiv1.PolicyAncestorStatus, _ int) bool {
+		return ancestor.ControllerName != controllerName
+	})
+	for _, ancestorRef := range ancestors {
+		if len(status.Ancestors) >= maxPolicyAncestors {
+			break
+		}
+		ancestorStatus := gwapiv1.PolicyAncestorStatus{
+			AncestorRef:    ancestorRef,
+			ControllerName: controllerName,
+		}
+		if current, found := lo.Find(currentAncestors, func(current gwapiv1.PolicyAncestorStatus) bool {
+			return current.ControllerName == controllerName && ancestorRefKey(current.AncestorRef) == ancestorRefKey(ancestorRef)
+		}); found {
+			ancestorStatus.Conditions = slices.Clone(current.Conditions)
+		}
+		for _, condition := range conditionsByAncestor[ancestorRefKey(ancestorRef)] {
+			meta.SetStatusCondition(&ancestorStatus.Conditions, condition)
+		}
+		status.Ancestors = append(status.Ancestors, ancestorStatus)
+	}
+	return status
+}
+
+// policyConditions returns the initial conditions of a policy for an ancestor, i.e. the conditions of a policy that
+// is accepted
+func policyConditions(generation int64, refsErr error, resolveRefs, detectConflicts bool) []metav1.Condition {
+	conditions := []metav1.Condition{
+		{
+			Type:               string(gwapiv1.PolicyConditionAccepted),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(gwapiv1.PolicyReasonAccepted),
+			Message:            "policy accepted",
+			ObservedGeneration: generation,
+		},
+	}
+	if resolveRefs {
+		resolvedRefs := metav1.Condition{
+			Type:               string(PolicyConditionResolvedRefs),
+			Status:             metav1.ConditionTrue,
+			Reason:             string(PolicyReasonResolvedRefs),
+			Message:            "all references resolved",
+			ObservedGeneration: generation,
+		}
+		if refsErr != nil {
+			resolvedRefs.Status = metav1.ConditionFalse
+			resolvedRefs.Reason = string(PolicyReasonInvalidRef)
+			resolvedRefs.Message = refsErr.Error()
+		}
+		conditions = append(conditions, resolvedRefs)
+	}
+	if detectConflicts {
+		conditions = append(conditions, metav1.Condition{
+			Type:               string(PolicyConditionConflicted),
+			Status:             metav1.ConditionFalse,
+			Reason:             string(PolicyReasonNoConflicts),
+			Message:            "no conflicts",
+			ObservedGeneration: generation,
+		})
+	}
+	return conditions
+}
+
+func setPolicyNotAccepted(condition *metav1.Condition, reason gwapiv1.PolicyConditionReason, message string) {
+	condition.Status = metav1.ConditionFalse
+	condition.Reason = string(reason)
+	condition.Message = message
+}
+
+// precedentPolicy returns the policy of the same kind attached to a targetable that takes precedence over a given
+// policy, i.e. the oldest one by creation timestamp and then by locator, if other than the given policy
+func precedentPolicy(policy Policy, targetable Targetable) (Policy, bool) {
+	policies := lo.Filter(targetable.Policies(), func(p Policy, _ int) bool {
+		return p.GroupVersionKind().GroupKind() == policy.GroupVersionKind().GroupKind()
+	})
+	if len(policies) < 2 {
+		return nil, false
+	}
+	creationTimestamp := func(p Policy) metav1.Time {
+		if obj, ok := p.(interface{ GetCreationTimestamp() metav1.Time }); ok {
+			return obj.GetCreationTimestamp()
+		}
+		return metav1.Time{}
+	}
+	precedent := slices.MinFunc(policies, func(a, b Policy) int {
+		if ta, tb := creationTimestamp(a), creationTimestamp(b); !ta.Equal(&tb) {
+			if ta.Before(&tb) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.GetLocator(), b.GetLocator())
+	})
+	return precedent, precedent.GetLocator() != policy.GetLocator()
+}
+
+// gatewayAncestors returns the Gateways at or above a targetable in the topology, sorted by locator
+func gatewayAncestors(topology *Topology, targetable Targetable) []Targetable {
+	var gateways []Targetable
+	visited := make(map[string]struct{})
+	queue := []Targetable{targetable}
+	for len(queue) > 0 {
+		var current Targetable
+		current, queue = queue[0], queue[1:]
+		if _, ok := visited[current.GetLocator()]; ok {
+			continue
+		}
+		visited[current.GetLocator()] = struct{}{}
+		if current.GroupVersionKind().GroupKind() == GatewayGroupKind {
+			gateways = append(gateways, current)
+			continue
+		}
+		queue = append(queue, topology.Targetables().Parents(current)...)
+	}
+	slices.SortFunc(gateways, func(a, b Targetable) int {
+		return strings.Compare(a.GetLocator(), b.GetLocator())
+	})
+	return gateways
+}
+
+func ancestorRefFromObject(obj Object) gwapiv1.ParentReference {
+	gk := obj.GroupVersionKind().GroupKind()
+	ancestorRef := gwapiv1.ParentReference{
+		Group: ptr.To(gwapiv1.Group(gk.Group)),
+		Kind:  ptr.To(gwapiv1.Kind(gk.Kind)),
+		Name:  gwapiv1.ObjectName(obj.GetName()),
+	}
+	if obj.GetNamespace() != "" {
+		ancestorRef.Namespace = ptr.To(gwapiv1.Namespace(obj.GetNamespace()))
+	}
+	return ancestorRef
+}
+
+// ancestorRefFromTargetRef returns an ancestor reference to the target of a policy, including the section name of the
+// target reference, if any
+func ancestorRefFromTargetRef(targetRef PolicyTargetReference) gwapiv1.ParentReference {
+	ancestorRef := ancestorRefFromObject(targetRef)
+	if name, sectionName, found := strings.Cut(string(ancestorRef.Name), string(nameSectionNameLocatorSeparator)); found {
+		ancestorRef.Name = gwapiv1.ObjectName(name)
+		ancestorRef.SectionName = ptr.To(gwapiv1.SectionName(sectionName))
+	}
+	return ancestorRef
+}
+
+// ancestorRefKey returns a unique key of an ancestor reference, for comparing ancestor references
+func ancestorRefKey(ancestorRef gwapiv1.ParentReference) string {
+	gk := schema.GroupKind{Group: string(ptr.Deref(ancestorRef.Group, gwapiv1.GroupName)), Kind: string(ptr.Deref(ancestorRef.Kind, "Gateway"))}
+	return fmt.Sprintf("%s/%s/%s#%s:%d", gk.String(), ptr.Deref(ancestorRef.Namespace, ""), ancestorRef.Name, ptr.Deref(ancestorRef.SectionName, ""), ptr.Deref(ancestorRef.Port, 0))
+}
@@ -0,0 +1,248 @@
+//go:build unit
+
+package machinery
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestPolicyStatusFromTopology(t *testing.T) {
+	const controllerName gwapiv1.GatewayController = "my-policy-controller"
+
+	buildPolicy := func(name string, kind gwapiv1.Kind, targetName gwapiv1.ObjectName, f ...func(*TestPolicy)) *TestPolicy {
+		return buildPolicy(append([]func(*TestPolicy){func(p *TestPolicy) {
+			p.Name = name
+			p.Generation = 2
+			p.Spec.TargetRef.Group = gwapiv1.GroupName
+			p.Spec.TargetRef.Kind = kind
+			p.Spec.TargetRef.Name = targetName
+		}}, f...)...)
+	}
+	olderPolicy := buildPolicy("older-policy", "Gateway", "my-gateway", func(p *TestPolicy) {
+		p.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	})
+	newerPolicy := buildPolicy("newer-policy", "Gateway", "my-gateway", func(p *TestPolicy) {
+		p.CreationTimestamp = metav1.NewTime(time.Now())
+	})
+	routePolicy := buildPolicy("route-policy", "HTTPRoute", "my-http-route")
+	gatewayClassPolicy := buildPolicy("gateway-class-policy", "GatewayClass", "my-gateway-class")
+	routeRulePolicy := buildPolicy("route-rule-policy", "HTTPRoute", "orphan-route", func(p *TestPolicy) {
+		p.Spec.TargetRef.SectionName = ptr.To(gwapiv1.SectionName("rule-1"))
+	})
+	missingTargetPolicy := buildPolicy("missing-target-policy", "Gateway", "other-gateway")
+	refusedPolicy := &allowedTargetsTestPolicy{
+		TestPolicy:  buildPolicy("refused-policy", "HTTPRoute", "my-http-route"),
+		targetKinds: []schema.GroupKind{GatewayGroupKind},
+	}
+
+	topology, err := NewGatewayAPITopology(
+		WithGatewayClasses(BuildGatewayClass()),
+		WithGateways(BuildGateway()),
+		WithHTTPRoutes(BuildHTTPRoute(), BuildHTTPRoute(func(r *gwapiv1.HTTPRoute) {
+			r.Name = "orphan-route"
+			r.Spec.ParentRefs[0].Name = "missing-gateway"
+		})),
+		WithServices(BuildService()),
+		WithGatewayAPITopologyPolicies(olderPolicy, newerPolicy, routePolicy, gatewayClassPolicy, routeRulePolicy, missingTargetPolicy, refusedPolicy),
+		ExpandHTTPRouteRules(),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	gatewayRef := gwapiv1.ParentReference{
+		Group:     ptr.To(gwapiv1.Group(gwapiv1.GroupName)),
+		Kind:      ptr.To(gwapiv1.Kind("Gateway")),
+		Namespace: ptr.To(gwapiv1.Namespace("my-namespace")),
+		Name:      "my-gateway",
+	}
+
+	testCases := []struct {
+		name              string
+		policy            Policy
+		options           []PolicyStatusOptionsFunc
+		expectedAncestors []string
+		expectedReasons   map[gwapiv1.PolicyConditionType]string
+	}{
+		{
+			name:              "accepted",
+			policy:            routePolicy,
+			expectedAncestors: []string{"gateway.networking.k8s.io/Gateway my-namespace/my-gateway"},
+			expectedReasons: map[gwapiv1.PolicyConditionType]string{
+				gwapiv1.PolicyConditionAccepted: "True/Accepted",
+			},
+		},
+		{
+			name:              "no gateway above the target",
+			policy:            gatewayClassPolicy,
+			expectedAncestors: []string{"gateway.networking.k8s.io/GatewayClass my-gateway-class"},
+			expectedReasons: map[gwapiv1.PolicyConditionType]string{
+				gwapiv1.PolicyConditionAccepted: "True/Accepted",
+			},
+		},
+		{
+			name:              "no gateway above the target section",
+			policy:            routeRulePolicy,
+			expectedAncestors: []string{"gateway.networking.k8s.io/HTTPRoute my-namespace/orphan-route (rule-1)"},
+			expectedReasons: map[gwapiv1.PolicyConditionType]string{
+				gwapiv1.PolicyConditionAccepted: "True/Accepted",
+			},
+		},
+		{
+			name:              "target not found",
+			policy:            missingTargetPolicy,
+			expectedAncestors: []string{"gateway.networking.k8s.io/Gateway my-namespace/other-gateway"},
+			expectedReasons: map[gwapiv1.PolicyConditionType]string{
+				gwapiv1.PolicyConditionAccepted: "False/TargetNotFound",
+			},
+		},
+		{
+			name:              "refused attachment",
+			policy:            refusedPolicy,
+			expectedAncestors: []string{"gateway.networking.k8s.io/Gateway my-namespace/my-gateway"},
+			expectedReasons: map[gwapiv1.PolicyConditionType]string{
+				gwapiv1.PolicyConditionAccepted: "False/Invalid",
+			},
+		},
+		{
+			name:              "unresolved refs",
+			policy:            routePolicy,
+			options:           []PolicyStatusOptionsFunc{WithPolicyRefsResolver(func(Policy, *Topology) error { return errors.New("secret not found") })},
+			expectedAncestors: []string{"gateway.networking.k8s.io/Gateway my-namespace/my-gateway"},
+			expectedReasons: map[gwapiv1.PolicyConditionType]string{
+				gwapiv1.PolicyConditionAccepted: "False/Invalid",
+				PolicyConditionResolvedRefs:     "False/InvalidRef",
+			},
+		},
+		{
+			name:              "resolved refs",
+			policy:            routePolicy,
+			options:           []PolicyStatusOptionsFunc{WithPolicyRefsResolver(func(Policy, *Topology) error { return nil })},
+			expectedAncestors: []string{"gateway.networking.k8s.io/Gateway my-namespace/my-gateway"},
+			expectedReasons: map[gwapiv1.PolicyConditionType]string{
+				gwapiv1.PolicyConditionAccepted: "True/Accepted",
+				PolicyConditionResolvedRefs:     "True/ResolvedRefs",
+			},
+		},
+		{
+			name:              "conflicts not detected by default",
+			policy:            newerPolicy,
+			expectedAncestors: []string{"gateway.networking.k8s.io/Gateway my-namespace/my-gateway"},
+			expectedReasons: map[gwapiv1.PolicyConditionType]string{
+				gwapiv1.PolicyConditionAccepted: "True/Accepted",
+			},
+		},
+		{
+			name:              "conflicted",
+			policy:            newerPolicy,
+			options:           []PolicyStatusOptionsFunc{DetectPolicyConflicts()},
+			expectedAncestors: []string{"gateway.networking.k8s.io/Gateway my-namespace/my-gateway"},
+			expectedReasons: map[gwapiv1.PolicyConditionType]string{
+				gwapiv1.PolicyConditionAccepted: "False/Conflicted",
+				PolicyConditionConflicted:       "True/Conflicted",
+			},
+		},
+		{
+			name:              "precedent in conflict",
+			policy:            olderPolicy,
+			options:           []PolicyStatusOptionsFunc{DetectPolicyConflicts()},
+			expectedAncestors: []string{"gateway.networking.k8s.io/Gateway my-namespace/my-gateway"},
+			expectedReasons: map[gwapiv1.PolicyConditionType]string{
+				gwapiv1.PolicyConditionAccepted: "True/Accepted",
+				PolicyConditionConflicted:       "False/NoConflicts",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status := PolicyStatusFromTopology(topology, tc.policy, controllerName, tc.options...)
+			ancestors := lo.Map(status.Ancestors, func(ancestor gwapiv1.PolicyAncestorStatus, _ int) string {
+				ref := ancestor.AncestorRef
+				name := string(ref.Name)
+				if ref.Namespace != nil {
+					name = namespacedName(string(*ref.Namespace), name)
+				}
+				if ref.SectionName != nil {
+					name = fmt.Sprintf("%s (%s)", name, *ref.SectionName)
+				}
+				return fmt.Sprintf("%s/%s %s", ptr.Deref(ref.Group, ""), ptr.Deref(ref.Kind, ""), name)
+			})
+			if !slices.Equal(ancestors, tc.expectedAncestors) {
+				t.Fatalf("expected ancestors %v, got %v", tc.expectedAncestors, ancestors)
+			}
+			conditions := status.Ancestors[0].Conditions
+			if len(conditions) != len(tc.expectedReasons) {
+				t.Errorf("expected %d conditions, got %v", len(tc.expectedReasons), conditions)
+			}
+			for conditionType, expected := range tc.expectedReasons {
+				condition := meta.FindStatusCondition(conditions, string(conditionType))
+				if condition == nil {
+					t.Errorf("expected condition %s, got none", conditionType)
+					continue
+				}
+				if got := fmt.Sprintf("%s/%s", condition.Status, condition.Reason); got != expected {
+					t.Errorf("expected condition %s to be %s, got %s", conditionType, expected, got)
+				}
+				if condition.ObservedGeneration != 2 {
+					t.Errorf("expected observed generation 2 in condition %s, got %d", conditionType, condition.ObservedGeneration)
+				}
+			}
+			if status.Ancestors[0].ControllerName != controllerName {
+				t.Errorf("expected controller name %s, got %s", controllerName, status.Ancestors[0].ControllerName)
+			}
+		})
+	}
+
+	t.Run("merged with the current status", func(t *testing.T) {
+		lastTransitionTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+		otherControllerAncestor := gwapiv1.PolicyAncestorStatus{
+			AncestorRef:    gatewayRef,
+			ControllerName: "other-controller",
+			Conditions:     []metav1.Condition{{Type: "Accepted", Status: metav1.ConditionFalse, Reason: "Invalid"}},
+		}
+		currentStatus := gwapiv1.PolicyStatus{
+			Ancestors: []gwapiv1.PolicyAncestorStatus{
+				otherControllerAncestor,
+				{
+					AncestorRef:    gatewayRef,
+					ControllerName: controllerName,
+					Conditions: []metav1.Condition{
+						{Type: "Accepted", Status: metav1.ConditionTrue, Reason: "Accepted", LastTransitionTime: lastTransitionTime},
+						{Type: "example.com/Enforced", Status: metav1.ConditionTrue, Reason: "Enforced", LastTransitionTime: lastTransitionTime},
+					},
+				},
+				{
+					AncestorRef:    gwapiv1.ParentReference{Name: "stale-gateway"},
+					ControllerName: controllerName,
+				},
+			},
+		}
+
+		status := PolicyStatusFromTopology(topology, routePolicy, controllerName, WithCurrentPolicyStatus(currentStatus))
+		if len(status.Ancestors) != 2 {
+			t.Fatalf("expected 2 ancestors, got %v", status.Ancestors)
+		}
+		if status.Ancestors[0].ControllerName != "other-controller" || !meta.IsStatusConditionFalse(status.Ancestors[0].Conditions, "Accepted") {
+			t.Errorf("expected the ancestor of the other controller to be preserved, got %v", status.Ancestors[0])
+		}
+		conditions := status.Ancestors[1].Conditions
+		if accepted := meta.FindStatusCondition(conditions, "Accepted"); accepted == nil || !accepted.LastTransitionTime.Equal(&lastTransitionTime) {
+			t.Errorf("expected the last transition time of the Accepted condition to be preserved, got %v", accepted)
+		}
+		if meta.FindStatusCondition(conditions, "example.com/Enforced") == nil {
+			t.Errorf("expected the conditions set by others to be preserved, got %v", conditions)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package machinery
+
+import (
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// SectionExpander defines the targetable sections of a kind of targetables, so policies can target the sections of
+// custom resources (e.g. the entries of a list field) the same way they target the listeners of a Gateway or the rules
+// of an HTTPRoute, i.e. with a target reference to the parent object and a section name.
+type SectionExpander struct {
+	// ParentGroupKind is the kind of the targetables whose sections are expanded.
+	ParentGroupKind schema.GroupKind
+	// SectionGroupKind is the kind of the targetable sections.
+	SectionGroupKind schema.GroupKind
+	// Sections enumerates the sections of a targetable of the parent kind. The name of each section must be unique within
+	// the parent.
+	Sections func(parent Targetable) []NamedSection
+}
+
+// NamedSection is a section of an object, e.g. an entry of a list field, identified by a name unique within the object.
+type NamedSection struct {
+	Name  gwapiv1.SectionName
+	Value any
+}
+
+// Expand returns the targetable sections of the targetables of the parent kind.
+func (e SectionExpander) Expand(targetables []Targetable) []*Section {
+	return lo.FlatMap(targetables, func(parent Targetable, _ int) []*Section {
+		if parent.GroupVersionKind().GroupKind() != e.ParentGroupKind {
+			return nil
+		}
+		return lo.Map(e.Sections(parent), func(section NamedSection, _ int) *Section {
+			return &Section{
+				NamedSection: section,
+				Parent:       parent,
+				groupKind:    e.SectionGroupKind,
+			}
+		})
+	})
+}
+
+// LinkFunc returns a link function that teaches a topology how to link the sections expanded from the targetables they
+// are strongly related to.
+func (e SectionExpander) LinkFunc() LinkFunc {
+	return LinkFunc{
+		From: e.ParentGroupKind,
+		To:   e.SectionGroupKind,
+		Func: func(child Object) []Object {
+			section := child.(*Section)
+			return []Object{section.Parent}
+		},
+	}
+}
+
+// Section is a targetable section of a targetable, expanded by a SectionExpander.
+// The value of the section is the one enumerated by the expander, e.g. an entry of a list field of the parent object.
+type Section struct {
+	NamedSection
+
+	Parent           Targetable
+	groupKind        schema.GroupKind
+	attachedPolicies []Policy
+}
+
+var _ Targetable = &Section{}
+
+func (s *Section) GroupVersionKind() schema.GroupVersionKind {
+	return s.groupKind.WithVersion("")
+}
+
+func (s *Section) SetGroupVersionKind(schema.GroupVersionKind) {}
+
+func (s *Section) GetLocator() string {
+	return namespacedSectionName(s.Parent.GetLocator(), s.Name)
+}
+
+func (s *Section) GetNamespace() string {
+	return s.Parent.GetNamespace()
+}
+
+func (s *Section) GetName() string {
+	return namespacedSectionName(s.Parent.GetName(), s.Name)
+}
+
+func (s *Section) SetPolicies(policies []Policy) {
+	s.attachedPolicies = policies
+}
+
+func (s *Section) Policies() []Policy {
+	return s.attachedPolicies
+}
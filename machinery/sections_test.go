@@ -0,0 +1,94 @@
+//go:build unit
+
+package machinery
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	gwapiv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestSectionExpanders(t *testing.T) {
+	orangeKind := schema.GroupKind{Group: TestGroupName, Kind: "Orange"}
+	segmentKind := schema.GroupKind{Group: TestGroupName, Kind: "OrangeSegment"}
+	seedKind := schema.GroupKind{Group: TestGroupName, Kind: "OrangeSeed"}
+
+	segments := SectionExpander{
+		ParentGroupKind:  orangeKind,
+		SectionGroupKind: segmentKind,
+		Sections: func(parent Targetable) []NamedSection {
+			return lo.Map(parent.(*Orange).ChildBananas, func(name string, _ int) NamedSection {
+				return NamedSection{Name: gwapiv1.SectionName(name), Value: len(name)}
+			})
+		},
+	}
+	seeds := SectionExpander{
+		ParentGroupKind:  segmentKind,
+		SectionGroupKind: seedKind,
+		Sections: func(parent Targetable) []NamedSection {
+			segment := parent.(*Section)
+			return lo.Times(segment.Value.(int)%2+1, func(i int) NamedSection {
+				return NamedSection{Name: gwapiv1.SectionName(fmt.Sprintf("%s.seed-%d", segment.Name, i+1))}
+			})
+		},
+	}
+
+	policy := buildPolicy(func(p *TestPolicy) {
+		p.Spec.TargetRef.Group = TestGroupName
+		p.Spec.TargetRef.Kind = "Orange"
+		p.Spec.TargetRef.Name = "orange-1"
+		p.Spec.TargetRef.SectionName = ptr.To(gwapiv1.SectionName("segment-a"))
+	})
+
+	topology, err := NewTopology(
+		WithTargetables(
+			&Orange{Name: "orange-1", Namespace: "my-namespace", ChildBananas: []string{"segment-a", "segment-bb"}},
+			&Orange{Name: "orange-2", Namespace: "my-namespace"},
+		),
+		WithPolicies(policy),
+		WithSectionExpanders(segments, seeds),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	links := make(map[string][]string)
+	for _, root := range topology.Targetables().Roots() {
+		linksFromTargetable(topology, root, links)
+	}
+	expectedLinks := map[string][]string{
+		"orange-1":            {"orange-1#segment-a", "orange-1#segment-bb"},
+		"orange-1#segment-a":  {"orange-1#segment-a#segment-a.seed-1", "orange-1#segment-a#segment-a.seed-2"},
+		"orange-1#segment-bb": {"orange-1#segment-bb#segment-bb.seed-1"},
+		"orange-2":            {},
+	}
+	for from, expectedTos := range expectedLinks {
+		tos := links[from]
+		slices.Sort(expectedTos)
+		slices.Sort(tos)
+		if !slices.Equal(expectedTos, tos) {
+			t.Errorf("expected links from %s to be %v, got %v", from, expectedTos, tos)
+		}
+	}
+
+	segment, found := lo.Find(topology.Targetables().Items(), func(targetable Targetable) bool {
+		return targetable.GetLocator() == "orange.example.test:my-namespace/orange-1#segment-a"
+	})
+	if !found {
+		t.Fatalf("expected section orange-1#segment-a in the topology")
+	}
+	if kind := segment.GroupVersionKind().GroupKind(); kind != segmentKind {
+		t.Errorf("expected section of kind %s, got %s", segmentKind, kind)
+	}
+	if value := segment.(*Section).Value; value != len("segment-a") {
+		t.Errorf("expected section value %d, got %v", len("segment-a"), value)
+	}
+	if policies := segment.Policies(); len(policies) != 1 || policies[0].GetName() != policy.GetName() {
+		t.Errorf("expected the policy attached to section orange-1#segment-a, got %v", policies)
+	}
+}
@@ -3,7 +3,9 @@ package machinery
 import (
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/emicklei/dot"
 	"github.com/samber/lo"
@@ -12,11 +14,13 @@ import (
 )
 
 type TopologyOptions struct {
-	Targetables []Targetable
-	Policies    []Policy
-	Objects     []Object
-	Links       []LinkFunc
-	AllowLoops  bool
+	Targetables      []Targetable
+	Policies         []Policy
+	Objects          []Object
+	Links            []LinkFunc
+	SectionExpanders []SectionExpander
+	AllowLoops       bool
+	Parallelism      int
 }
 
 type LinkFunc struct {
@@ -25,6 +29,49 @@ type LinkFunc struct {
 	Func func(child Object) (parents []Object)
 }
 
+// IndexedLinkFunc returns a link function that finds the parents of a child by looking up the keys of the child in an
+// index of parents, instead of scanning all the parents for every child.
+// The index is built once, when IndexedLinkFunc is called, by mapping each key returned by `parentKeys` to the parents
+// that hold the key. The keys to look up for each child are returned by `childKeys`.
+// The parents of a child are returned in the order of the keys of the child, then in the order the parents were given,
+// without duplicates.
+func IndexedLinkFunc[T Object](from, to schema.GroupKind, parents []T, parentKeys func(T) []string, childKeys func(Object) []string) LinkFunc {
+	index := make(map[string][]Object)
+	for _, parent := range parents {
+		for _, key := range lo.Uniq(parentKeys(parent)) {
+			index[key] = append(index[key], parent)
+		}
+	}
+	return LinkFunc{
+		From: from,
+		To:   to,
+		Func: func(child Object) []Object {
+			keys := childKeys(child)
+			if len(keys) == 1 {
+				return slices.Clone(index[keys[0]])
+			}
+			return lo.UniqBy(lo.FlatMap(keys, func(key string, _ int) []Object {
+				return index[key]
+			}), func(parent Object) string {
+				return parent.GetLocator()
+			})
+		},
+	}
+}
+
+// filterLinkFunc returns a copy of a link function that only links the parents that satisfy the given predicate
+func filterLinkFunc(link LinkFunc, predicate func(parent, child Object) bool) LinkFunc {
+	return LinkFunc{
+		From: link.From,
+		To:   link.To,
+		Func: func(child Object) []Object {
+			return lo.Filter(link.Func(child), func(parent Object, _ int) bool {
+				return predicate(parent, child)
+			})
+		},
+	}
+}
+
 type TopologyOptionsFunc func(*TopologyOptions)
 
 // WithTargetables adds targetables to the options to initialize a new topology.
@@ -63,6 +110,15 @@ func WithLinks(links ...LinkFunc) TopologyOptionsFunc {
 	}
 }
 
+// WithSectionExpanders adds section expanders to the options to initialize a new topology.
+// The sections of the targetables of the parent kind of each expander are added to the topology as targetables, linked
+// from their parents. The expanders are applied in order, so an expander can expand the sections of a previous one.
+func WithSectionExpanders(expanders ...SectionExpander) TopologyOptionsFunc {
+	return func(o *TopologyOptions) {
+		o.SectionExpanders = append(o.SectionExpanders, expanders...)
+	}
+}
+
 // AllowLoops allows the creation of a topology that may contain loops
 func AllowLoops() TopologyOptionsFunc {
 	return func(o *TopologyOptions) {
@@ -70,21 +126,51 @@ func AllowLoops() TopologyOptionsFunc {
 	}
 }
 
+// WithParallelism sets the maximum number of link functions evaluated concurrently when building the topology.
+// Edges are still added to the topology in the order of the link functions, so the output is the same as the one of a
+// topology built serially. Link functions must be safe for concurrent use. Values lower than 2 disable parallelism.
+func WithParallelism(n int) TopologyOptionsFunc {
+	return func(o *TopologyOptions) {
+		o.Parallelism = n
+	}
+}
+
 // NewTopology returns a network of targetable resources, attached policies, and other kinds of objects.
 // The topology is represented as a directed acyclic graph (DAG) with the structure given by link functions.
 // The links between policies to targteables are inferred from the policies' target references.
+// Target references of policies that declare allowed target kinds (PolicyWithAllowedTargets) to targetables of other
+// kinds are refused and reported by Topology.RefusedPolicyAttachments().
 // The targetables, policies, objects and link functions are provided as options.
+// Sections of the targetables defined by section expanders are added as targetables as well, linked from their parents.
 func NewTopology(options ...TopologyOptionsFunc) (*Topology, error) {
 	o := &TopologyOptions{}
 	for _, f := range options {
 		f(o)
 	}
 
+	for _, expander := range o.SectionExpanders {
+		o.Targetables = append(o.Targetables, lo.Map(expander.Expand(o.Targetables), func(section *Section, _ int) Targetable {
+			return section
+		})...)
+		o.Links = append(o.Links, expander.LinkFunc())
+	}
+
+	targetablesByLocator := lo.SliceToMap(o.Targetables, associateLocator[Targetable])
+
 	policies := o.Policies
 	policiesByTargetRef := make(map[string][]Policy)
+	acceptedTargetRefs := make([][]PolicyTargetReference, len(policies))
+	var refusedPolicyAttachments []RefusedPolicyAttachment
 	for i := range policies {
 		policy := policies[i]
 		for _, targetRef := range policy.GetTargetRefs() {
+			if targetable, found := targetablesByLocator[targetRef.GetLocator()]; found {
+				if reason, allowed := policyAllowsTarget(policy, targetRef, targetable); !allowed {
+					refusedPolicyAttachments = append(refusedPolicyAttachments, RefusedPolicyAttachment{Policy: policy, TargetRef: targetRef, Reason: reason})
+					continue
+				}
+			}
+			acceptedTargetRefs[i] = append(acceptedTargetRefs[i], targetRef)
 			if policiesByTargetRef[targetRef.GetLocator()] == nil {
 				policiesByTargetRef[targetRef.GetLocator()] = make([]Policy, 0)
 			}
@@ -101,21 +187,19 @@ func NewTopology(options ...TopologyOptionsFunc) (*Topology, error) {
 
 	addObjectsToGraph(graph, o.Objects)
 	addTargetablesToGraph(graph, targetables)
-	addPoliciesToGraph(graph, policies)
+	addPoliciesToGraph(graph, policies, acceptedTargetRefs)
 
 	linkables := append(o.Objects, lo.Map(targetables, AsObject[Targetable])...)
 	linkables = append(linkables, lo.Map(policies, AsObject[Policy])...)
 
-	for _, link := range o.Links {
-		children := lo.Filter(linkables, func(l Object, _ int) bool {
-			return l.GroupVersionKind().GroupKind() == link.To
-		})
-		for _, child := range children {
-			for _, parent := range link.Func(child) {
-				if parent != nil {
-					addEdgeToGraph(graph, fmt.Sprintf("%s -> %s", link.From.Kind, link.To.Kind), parent, child)
-				}
-			}
+	edges := make([][]edge, len(o.Links))
+	forEach(o.Parallelism, len(o.Links), func(i int) {
+		edges[i] = linkEdges(o.Links[i], linkables)
+	})
+	for i, link := range o.Links {
+		name := fmt.Sprintf("%s -> %s", link.From.Kind, link.To.Kind)
+		for _, e := range edges[i] {
+			addEdgeToGraph(graph, name, e.parent, e.child)
 		}
 	}
 
@@ -125,19 +209,44 @@ func NewTopology(options ...TopologyOptionsFunc) (*Topology, error) {
 	}
 
 	return &Topology{
-		graph:       graph,
-		objects:     lo.SliceToMap(o.Objects, associateLocator[Object]),
-		targetables: lo.SliceToMap(targetables, associateLocator[Targetable]),
-		policies:    lo.SliceToMap(policies, associateLocator[Policy]),
+		graph:                    graph,
+		objects:                  lo.SliceToMap(o.Objects, associateLocator[Object]),
+		targetables:              lo.SliceToMap(targetables, associateLocator[Targetable]),
+		policies:                 lo.SliceToMap(policies, associateLocator[Policy]),
+		refusedPolicyAttachments: refusedPolicyAttachments,
 	}, err
 }
 
+// policyAllowsTarget tells whether a policy can be attached to the targetable its target reference points to,
+// according to the target kinds and section kinds allowed by the policy. If not allowed, it returns the reason why.
+// A targetable of a kind other than the one of the target reference is a section of the object referred.
+func policyAllowsTarget(policy Policy, targetRef PolicyTargetReference, targetable Targetable) (string, bool) {
+	p, ok := policy.(PolicyWithAllowedTargets)
+	if !ok {
+		return "", true
+	}
+	policyKind := policy.GroupVersionKind().Kind
+	targetKind := targetRef.GroupVersionKind().GroupKind()
+	if allowedKinds := p.AllowedTargetKinds(); len(allowedKinds) > 0 && !lo.Contains(allowedKinds, targetKind) {
+		return fmt.Sprintf("%s cannot target kind %s", policyKind, targetKind.String()), false
+	}
+	sectionKind := targetable.GroupVersionKind().GroupKind()
+	if sectionKind == targetKind {
+		return "", true
+	}
+	if allowedKinds := p.AllowedSectionKinds(); len(allowedKinds) > 0 && !lo.Contains(allowedKinds, sectionKind) {
+		return fmt.Sprintf("%s cannot target sections of kind %s", policyKind, sectionKind.String()), false
+	}
+	return "", true
+}
+
 // Topology models a network of related targetables and respective policies attached to them.
 type Topology struct {
-	graph       *dot.Graph
-	targetables map[string]Targetable
-	policies    map[string]Policy
-	objects     map[string]Object
+	graph                    *dot.Graph
+	targetables              map[string]Targetable
+	policies                 map[string]Policy
+	objects                  map[string]Object
+	refusedPolicyAttachments []RefusedPolicyAttachment
 }
 
 // Targetables returns all targetable nodes in the topology.
@@ -182,6 +291,12 @@ func (t *Topology) All() *collection[Object] {
 	}
 }
 
+// RefusedPolicyAttachments returns the target references of policies to targetables that the policies are not allowed
+// to be attached to, in the order of the policies and target references supplied to the topology.
+func (t *Topology) RefusedPolicyAttachments() []RefusedPolicyAttachment {
+	return slices.Clone(t.refusedPolicyAttachments)
+}
+
 func (t *Topology) ToDot() string {
 	return t.graph.String()
 }
@@ -211,14 +326,16 @@ func addTargetablesToGraph[T Targetable](graph *dot.Graph, targetables []T) {
 	}
 }
 
-func addPoliciesToGraph[T Policy](graph *dot.Graph, policies []T) {
+// addPoliciesToGraph adds the policies to the graph, along with the Policy -> Target edges for the target references of
+// each policy, given in the same order as the policies
+func addPoliciesToGraph[T Policy](graph *dot.Graph, policies []T, targetRefs [][]PolicyTargetReference) {
 	for i, policyNode := range addObjectsToGraph(graph, policies) {
 		policyNode.Attrs(
 			"shape", "note",
 			"style", "dashed",
 		)
 		// Policy -> Target edges
-		for _, targetRef := range policies[i].GetTargetRefs() {
+		for _, targetRef := range targetRefs[i] {
 			targetNode, found := graph.FindNodeById(string(targetRef.GetLocator()))
 			if !found {
 				continue
@@ -230,6 +347,54 @@ func addPoliciesToGraph[T Policy](graph *dot.Graph, policies []T) {
 	}
 }
 
+// edge is a link between a parent and a child object, established by a link function
+type edge struct {
+	parent Object
+	child  Object
+}
+
+// linkEdges returns the edges established by a link function between the linkable objects, in the order of the
+// children
+func linkEdges(link LinkFunc, linkables []Object) []edge {
+	var edges []edge
+	for _, child := range linkables {
+		if child.GroupVersionKind().GroupKind() != link.To {
+			continue
+		}
+		for _, parent := range link.Func(child) {
+			if parent != nil {
+				edges = append(edges, edge{parent: parent, child: child})
+			}
+		}
+	}
+	return edges
+}
+
+// forEach calls f for each index in [0, n), with up to `parallelism` calls running concurrently.
+// It returns after all calls have returned.
+func forEach(parallelism, n int, f func(i int)) {
+	if parallelism < 2 || n < 2 {
+		for i := range n {
+			f(i)
+		}
+		return
+	}
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallelism)
+	for i := range n {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			f(i)
+		}()
+	}
+	wg.Wait()
+}
+
 func addEdgeToGraph(graph *dot.Graph, name string, parent, child Object) {
 	p, foundParent := graph.FindNodeById(string(parent.GetLocator()))
 	c, foundChild := graph.FindNodeById(string(child.GetLocator()))
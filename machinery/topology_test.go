@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/samber/lo"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func TestTopologyRoots(t *testing.T) {
@@ -686,3 +687,52 @@ func TestTopologyAllPaths(t *testing.T) {
 		})
 	}
 }
+
+func TestIndexedLinkFunc(t *testing.T) {
+	apples := []*Apple{
+		{Name: "apple-1"},
+		{Name: "apple-2"},
+		{Name: "apple-3"},
+	}
+	link := IndexedLinkFunc(
+		schema.GroupKind{Group: TestGroupName, Kind: "Apple"},
+		schema.GroupKind{Group: TestGroupName, Kind: "Orange"},
+		apples,
+		func(apple *Apple) []string { return []string{apple.Name} },
+		func(child Object) []string { return child.(*Orange).AppleParents },
+	)
+
+	testCases := []struct {
+		name            string
+		orange          *Orange
+		expectedParents []string
+	}{
+		{
+			name:   "no keys",
+			orange: &Orange{Name: "orange-1"},
+		},
+		{
+			name:            "single key",
+			orange:          &Orange{Name: "orange-1", AppleParents: []string{"apple-2"}},
+			expectedParents: []string{"apple-2"},
+		},
+		{
+			name:            "multiple keys in order without duplicates",
+			orange:          &Orange{Name: "orange-1", AppleParents: []string{"apple-3", "apple-1", "apple-3"}},
+			expectedParents: []string{"apple-3", "apple-1"},
+		},
+		{
+			name:            "unknown keys",
+			orange:          &Orange{Name: "orange-1", AppleParents: []string{"apple-4", "apple-2"}},
+			expectedParents: []string{"apple-2"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parents := lo.Map(link.Func(tc.orange), func(parent Object, _ int) string { return parent.GetName() })
+			if !slices.Equal(parents, tc.expectedParents) {
+				t.Errorf("expected parents %v, got %v", tc.expectedParents, parents)
+			}
+		})
+	}
+}
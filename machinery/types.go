@@ -57,6 +57,27 @@ type Policy interface {
 	Merge(Policy) Policy
 }
 
+// PolicyWithAllowedTargets is a Policy that declares the kinds of targetables it can be attached to.
+// Attachments of these policies to targetables of other kinds are refused when building a topology.
+type PolicyWithAllowedTargets interface {
+	Policy
+
+	// AllowedTargetKinds returns the group kinds the target references of the policy can point to, e.g. Gateway.
+	// An empty list allows any kind.
+	AllowedTargetKinds() []schema.GroupKind
+	// AllowedSectionKinds returns the group kinds of the sections the target references of the policy can point to with
+	// a section name, e.g. Listener. An empty list allows any kind of section of the allowed target kinds.
+	AllowedSectionKinds() []schema.GroupKind
+}
+
+// RefusedPolicyAttachment is a target reference of a policy to a targetable that the policy is not allowed to be
+// attached to.
+type RefusedPolicyAttachment struct {
+	Policy    Policy
+	TargetRef PolicyTargetReference
+	Reason    string
+}
+
 // PolicyTargetReference is a generic interface for all kinds of Gateway API policy target references.
 // It implements the Object interface for the referent.
 type PolicyTargetReference interface {